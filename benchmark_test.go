@@ -94,6 +94,18 @@ func BenchmarkParseComplex(b *testing.B) {
 	}
 }
 
+func BenchmarkParseLongLine(b *testing.B) {
+	content := "LONG=" + strings.Repeat("x", 256*1024) + "\nQUOTED=\"" + strings.Repeat("y", 256*1024) + "\"\n"
+
+	b.SetBytes(int64(len(content)))
+	for i := 0; i < b.N; i++ {
+		_, err := Parse(strings.NewReader(content))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func BenchmarkUnmarshal(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_, err := Unmarshal(mediumEnvContent)
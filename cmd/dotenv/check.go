@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mew-sh/dotenv"
+)
+
+func cmdCheck(args []string) int {
+	flags, files := newFlagSet("check", "[-f FILES] [-schema FILE] [-allow-unknown] [-json]")
+	schemaFile := flags.String("schema", ".env.example", "annotated example file, or a .json schema")
+	allowUnknown := flags.Bool("allow-unknown", false, "do not report keys missing from the schema")
+	asJSON := flags.Bool("json", false, "print the problems as JSON")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	schema, err := dotenv.ReadSchema(*schemaFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading schema: %v\n", err)
+		return 2
+	}
+
+	env, err := dotenv.Read(targetFiles(*files)...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading .env files: %v\n", err)
+		return 2
+	}
+
+	problems := []dotenv.Problem{}
+	var verr *dotenv.ValidationError
+	if err := schema.Validate(env); errors.As(err, &verr) {
+		for _, p := range verr.Problems {
+			if p.Kind != dotenv.ProblemUnknown || !*allowUnknown {
+				problems = append(problems, p)
+			}
+		}
+	}
+
+	if *asJSON {
+		if err := writeJSON(os.Stdout, problems); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	} else {
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+	}
+
+	if len(problems) > 0 {
+		return 1
+	}
+	return 0
+}
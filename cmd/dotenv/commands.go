@@ -0,0 +1,295 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/mew-sh/dotenv"
+)
+
+// commands maps subcommand names to their implementations. Each receives the
+// arguments following its name and returns the process exit code.
+var commands = map[string]func(args []string) int{
+	"get":     cmdGet,
+	"set":     cmdSet,
+	"unset":   cmdUnset,
+	"list":    cmdList,
+	"keys":    cmdKeys,
+	"run":     cmdRun,
+	"export":  cmdExport,
+	"convert": cmdConvert,
+	"diff":    cmdDiff,
+	"lint":    cmdLint,
+	"fmt":     cmdFmt,
+	"check":   cmdCheck,
+	"init":    cmdInit,
+	"docs":    cmdDocs,
+	"gen":     cmdGen,
+	"encrypt": cmdEncrypt,
+	"decrypt": cmdDecrypt,
+	"scan":    cmdScan,
+}
+
+// newFlagSet creates the flag set for a subcommand. The -f flag defaults to
+// the value given before the subcommand name.
+func newFlagSet(name, usage string) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: dotenv %s %s\n", name, usage)
+		flags.PrintDefaults()
+	}
+	files := flags.String("f", *envFiles, "comma separated paths to .env files")
+	return flags, files
+}
+
+// targetFiles returns the files named by spec, defaulting to .env
+func targetFiles(spec string) []string {
+	if files := splitFiles(spec); len(files) > 0 {
+		return files
+	}
+	return []string{dotenv.DefaultEnvFile}
+}
+
+func cmdGet(args []string) int {
+	flags, files := newFlagSet("get", "[-f FILES] KEY")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	env, err := dotenv.Read(targetFiles(*files)...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading .env files: %v\n", err)
+		return 1
+	}
+
+	key := flags.Arg(0)
+	value, ok := env[key]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%s is not set\n", key)
+		return 1
+	}
+
+	fmt.Println(value)
+	return 0
+}
+
+func cmdSet(args []string) int {
+	flags, files := newFlagSet("set", "[-f FILES] KEY=VALUE...")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	// Later files take precedence, so write to the last one
+	paths := targetFiles(*files)
+	filename := paths[len(paths)-1]
+
+	doc, err := readDocumentOrEmpty(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading .env file: %v\n", err)
+		return 1
+	}
+
+	for _, assignment := range flags.Args() {
+		key, value, ok := strings.Cut(assignment, "=")
+		if !ok || !isValidKey(key) {
+			fmt.Fprintf(os.Stderr, "Invalid assignment %q, expected KEY=VALUE\n", assignment)
+			return 2
+		}
+		doc.Set(key, value)
+	}
+
+	if err := doc.Save(filename); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing .env file: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func cmdUnset(args []string) int {
+	flags, files := newFlagSet("unset", "[-f FILES] KEY...")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	for _, filename := range targetFiles(*files) {
+		doc, err := dotenv.ReadDocument(filename)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading .env file: %v\n", err)
+			return 1
+		}
+
+		changed := false
+		for _, key := range flags.Args() {
+			if doc.Unset(key) {
+				changed = true
+			}
+		}
+
+		if changed {
+			if err := doc.Save(filename); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing .env file: %v\n", err)
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+func cmdList(args []string) int {
+	flags, files := newFlagSet("list", "[-f FILES] [-show-secrets]")
+	showSecrets := flags.Bool("show-secrets", false, "print the values of secret keys instead of "+dotenv.Redacted)
+	flags.Parse(args)
+
+	paths := targetFiles(*files)
+	env, err := dotenv.Read(paths...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading .env files: %v\n", err)
+		return 1
+	}
+	if !*showSecrets {
+		env = secretPolicy(paths).RedactMap(env)
+	}
+
+	content, err := dotenv.Marshal(env)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting variables: %v\n", err)
+		return 1
+	}
+
+	if content != "" {
+		fmt.Println(content)
+	}
+	return 0
+}
+
+func cmdKeys(args []string) int {
+	flags, files := newFlagSet("keys", "[-f FILES]")
+	flags.Parse(args)
+
+	env, err := dotenv.Read(targetFiles(*files)...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading .env files: %v\n", err)
+		return 1
+	}
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Println(key)
+	}
+	return 0
+}
+
+func cmdExport(args []string) int {
+	flags, files := newFlagSet("export", "[-f FILES] [-shell bash|zsh|fish|powershell|cmd|nushell]")
+	shellName := flags.String("shell", defaultShell(), "shell syntax to print")
+	flags.Parse(args)
+
+	shell, err := dotenv.ParseShell(*shellName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	env, err := dotenv.Read(targetFiles(*files)...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading .env files: %v\n", err)
+		return 1
+	}
+
+	out, err := dotenv.Export(env, shell)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Print(out)
+	return 0
+}
+
+// defaultShell guesses the user's shell from $SHELL, falling back to
+// PowerShell on Windows and bash elsewhere
+func defaultShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		name := filepath.Base(shell)
+		if _, err := dotenv.ParseShell(name); err == nil {
+			return name
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return string(dotenv.ShellPowerShell)
+	}
+	return string(dotenv.ShellBash)
+}
+
+// secretPolicy returns the redaction policy for files: keys matching the
+// default patterns, and keys marked @secret in the .env.example next to any
+// of the files. Unreadable examples are ignored.
+func secretPolicy(files []string) dotenv.RedactPolicy {
+	var policy dotenv.RedactPolicy
+	seen := make(map[string]bool)
+
+	for _, file := range files {
+		example := filepath.Join(filepath.Dir(file), ".env.example")
+		if seen[example] {
+			continue
+		}
+		seen[example] = true
+
+		if schema, err := dotenv.ReadSchema(example); err == nil {
+			policy.Keys = append(policy.Keys, schema.RedactPolicy().Keys...)
+		}
+	}
+	return policy
+}
+
+// readDocumentOrEmpty reads filename, returning an empty document if it
+// does not exist yet
+func readDocumentOrEmpty(filename string) (*dotenv.Document, error) {
+	doc, err := dotenv.ReadDocument(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &dotenv.Document{}, nil
+	}
+	return doc, err
+}
+
+// isValidKey reports whether key is a valid variable name
+func isValidKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for i, c := range key {
+		switch {
+		case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mew-sh/dotenv"
+)
+
+func cmdConvert(args []string) int {
+	flags, files := newFlagSet("convert", "[-f FILES] [-from dotenv|json|properties|ini] -to dotenv|json|yaml|toml|docker|shell|systemd|properties|ini [-out FILE]")
+	from := flags.String("from", "dotenv", "input format; use -f - to read standard input")
+	to := flags.String("to", "", "output format")
+	out := flags.String("out", "", "write to FILE instead of standard output")
+	separator := flags.String("separator", "_", "with -from json, separator joining nested names")
+	keyCase := flags.String("case", "upper", "with -from json, key case: upper, lower or preserve")
+	arrays := flags.String("arrays", "index", "with -from json, encode arrays as index (KEY_0, KEY_1) or join")
+	arraySeparator := flags.String("array-separator", ",", "with -arrays join, separator between elements")
+	flags.Parse(args)
+
+	inFormat, err := dotenv.ParseFormat(*from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	outFormat, err := dotenv.ParseFormat(*to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	opts := dotenv.JSONOptions{Separator: *separator, ArraySeparator: *arraySeparator}
+	switch *keyCase {
+	case "upper":
+		opts.KeyCase = dotenv.CaseUpper
+	case "lower":
+		opts.KeyCase = dotenv.CaseLower
+	case "preserve":
+		opts.KeyCase = dotenv.CasePreserve
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown key case %q\n", *keyCase)
+		return 2
+	}
+	switch *arrays {
+	case "index":
+	case "join":
+		opts.JoinArrays = true
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown array encoding %q\n", *arrays)
+		return 2
+	}
+
+	env, err := readInput(inFormat, targetFiles(*files), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	content, err := dotenv.MarshalFormat(env, outFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *out == "" {
+		fmt.Print(content)
+		return 0
+	}
+
+	if err := os.WriteFile(*out, []byte(content), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *out, err)
+		return 1
+	}
+	return 0
+}
+
+// readInput reads and merges files in the given format; "-" reads standard
+// input
+func readInput(format dotenv.Format, files []string, opts dotenv.JSONOptions) (map[string]string, error) {
+	result := make(map[string]string)
+
+	for _, file := range files {
+		var env map[string]string
+		err := withInput(file, func(r io.Reader) error {
+			var err error
+			switch format {
+			case dotenv.FormatDotenv:
+				env, err = dotenv.Parse(r)
+			case dotenv.FormatJSON:
+				env, err = dotenv.ParseJSON(r, opts)
+			case dotenv.FormatProperties:
+				env, err = dotenv.ParseProperties(r)
+			case dotenv.FormatINI:
+				env, err = dotenv.ParseINI(r)
+			default:
+				err = fmt.Errorf("cannot read %s input", format)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		// Later files take precedence
+		for key, value := range env {
+			result[key] = value
+		}
+	}
+
+	return result, nil
+}
+
+// withInput opens file, or standard input for "-", and passes it to fn
+func withInput(file string, fn func(io.Reader) error) error {
+	if file == "-" {
+		return fn(os.Stdin)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return fn(f)
+}
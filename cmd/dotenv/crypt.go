@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mew-sh/dotenv"
+)
+
+func cmdEncrypt(args []string) int {
+	flags, files := newFlagSet("encrypt", "[-f FILES] [KEY...]")
+	flags.Parse(args)
+
+	key, err := dotenv.LoadKey()
+	if errors.Is(err, dotenv.ErrNoKey) {
+		key, err = createKeyFile()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	return rewriteValues(targetFiles(*files), flags.Args(), func(name, value string, all bool) (string, bool, error) {
+		if value == "" || dotenv.IsEncrypted(value) {
+			return "", false, nil
+		}
+		// Decrypted values are not expanded, so encrypting a reference
+		// would change it; only do so when asked for by name
+		if all && strings.Contains(value, "$") {
+			fmt.Fprintf(os.Stderr, "Skipping %s: its value contains $; name it explicitly to encrypt it\n", name)
+			return "", false, nil
+		}
+		encrypted, err := dotenv.EncryptValue(key, name, value)
+		return encrypted, err == nil, err
+	})
+}
+
+func cmdDecrypt(args []string) int {
+	flags, files := newFlagSet("decrypt", "[-f FILES] [KEY...]")
+	flags.Parse(args)
+
+	key, err := dotenv.LoadKey()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	return rewriteValues(targetFiles(*files), flags.Args(), func(name, value string, _ bool) (string, bool, error) {
+		if !dotenv.IsEncrypted(value) {
+			return "", false, nil
+		}
+		decrypted, err := dotenv.DecryptValue(key, name, value)
+		return decrypted, err == nil, err
+	})
+}
+
+// rewriteValues passes the values of keys (every key when none are named) in
+// each file to transform, saving the files whose values changed. Only the
+// value portions of those lines are rewritten. Files assigning a key more
+// than once are refused before any file is changed, since only the last
+// assignment would be rewritten.
+func rewriteValues(files, keys []string, transform func(name, value string, all bool) (string, bool, error)) int {
+	docs := make([]*dotenv.Document, len(files))
+	for i, filename := range files {
+		doc, err := dotenv.ReadDocument(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading .env file: %v\n", err)
+			return 1
+		}
+		if dups := duplicateKeys(doc); len(dups) > 0 {
+			for _, d := range dups {
+				d.File = filename
+				fmt.Fprintf(os.Stderr, "Error: %s\n", d)
+			}
+			fmt.Fprintf(os.Stderr, "Remove the duplicate keys first, e.g. with dotenv lint -fix %s\n", filename)
+			return 1
+		}
+		docs[i] = doc
+	}
+
+	for i, filename := range files {
+		doc := docs[i]
+
+		names := keys
+		if len(names) == 0 {
+			names = doc.Keys()
+		}
+
+		changed := false
+		for _, name := range names {
+			value, ok := doc.Get(name)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Error: %s is not set in %s\n", name, filename)
+				return 1
+			}
+
+			updated, ok, err := transform(name, value, len(keys) == 0)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: %s: %v\n", filename, name, err)
+				return 1
+			}
+			if ok {
+				doc.Set(name, updated)
+				changed = true
+			}
+		}
+
+		if changed {
+			if err := doc.Save(filename); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing .env file: %v\n", err)
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+// duplicateKeys returns the duplicate-key problems Lint reports for doc
+func duplicateKeys(doc *dotenv.Document) []dotenv.Diagnostic {
+	var disable []string
+	for _, rule := range dotenv.LintRules() {
+		if rule.Name != dotenv.RuleDuplicateKey {
+			disable = append(disable, rule.Name)
+		}
+	}
+	diags, _ := dotenv.Lint(doc, dotenv.LintOptions{Disable: disable})
+	return diags
+}
+
+// createKeyFile generates a key and stores it where LoadKey looks for it
+func createKeyFile() ([]byte, error) {
+	key, err := dotenv.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	filename := os.Getenv("DOTENV_KEY_FILE")
+	if filename == "" {
+		filename = dotenv.DefaultKeyFile
+	}
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key file: %w", err)
+	}
+	if _, err := fmt.Fprintln(f, hex.EncodeToString(key)); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write key file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Created %s; keep it out of version control\n", filename)
+	return key, nil
+}
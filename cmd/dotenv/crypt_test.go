@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mew-sh/dotenv"
+)
+
+func TestEncryptRefusesDuplicateKeys(t *testing.T) {
+	t.Setenv("DOTENV_PRIVATE_KEY", strings.Repeat("ab", 32))
+	dir := t.TempDir()
+	clean := filepath.Join(dir, ".env")
+	dup := filepath.Join(dir, ".env.local")
+	cleanContent := "API_KEY=plain\n"
+	dupContent := "DB_PASSWORD=one\nHOST=db\nDB_PASSWORD=two\n"
+	if err := os.WriteFile(clean, []byte(cleanContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dup, []byte(dupContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// No file is changed, including those listed before the duplicate
+	for _, run := range []func([]string) int{cmdEncrypt, cmdDecrypt} {
+		for _, args := range [][]string{{"-f", clean + "," + dup}, {"-f", dup, "DB_PASSWORD"}} {
+			if code := run(args); code != 1 {
+				t.Errorf("Expected exit code 1 for %v, got %d", args, code)
+			}
+		}
+	}
+	for file, want := range map[string]string{clean: cleanContent, dup: dupContent} {
+		if data, _ := os.ReadFile(file); string(data) != want {
+			t.Errorf("Expected %s to be left alone, got:\n%s", file, data)
+		}
+	}
+
+	// Once the duplicates are removed, no value is left in plain text
+	doc, err := dotenv.ReadDocument(dup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dotenv.FixLint(doc, dotenv.LintOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Save(dup); err != nil {
+		t.Fatal(err)
+	}
+	if code := cmdEncrypt([]string{"-f", dup}); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	data, _ := os.ReadFile(dup)
+	if strings.Contains(string(data), "one") || strings.Contains(string(data), "two") {
+		t.Errorf("Expected every value to be encrypted, got:\n%s", data)
+	}
+	env, err := dotenv.Read(dup)
+	if err != nil || env["DB_PASSWORD"] != "two" {
+		t.Errorf("Expected DB_PASSWORD=two after decryption, got %v, %v", env, err)
+	}
+}
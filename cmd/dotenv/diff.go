@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/mew-sh/dotenv"
+)
+
+// ANSI colours used by diff output
+const (
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorReset  = "\033[0m"
+)
+
+// diffColors maps the sign of a diff line to its colour
+var diffColors = map[string]string{
+	"+": colorGreen,
+	"-": colorRed,
+	"~": colorYellow,
+}
+
+// diffEntry is one key in the JSON output of dotenv diff. Values are left
+// out when -redact is given, and for secret keys unless -show-secrets is.
+type diffEntry struct {
+	Key   string  `json:"key"`
+	Value *string `json:"value,omitempty"`
+	Old   *string `json:"old,omitempty"`
+	New   *string `json:"new,omitempty"`
+}
+
+// diffReport is the JSON output of dotenv diff
+type diffReport struct {
+	Added   []diffEntry `json:"added"`
+	Removed []diffEntry `json:"removed"`
+	Changed []diffEntry `json:"changed"`
+}
+
+func cmdDiff(args []string) int {
+	flags, files := newFlagSet("diff", "[-env] [-redact] [-show-secrets] [-json] [-color auto|always|never] A [B]")
+	live := flags.Bool("env", false, "compare A (or the -f files) with the current environment")
+	redact := flags.Bool("redact", false, "print keys only, never values")
+	showSecrets := flags.Bool("show-secrets", false, "print the values of secret keys too")
+	asJSON := flags.Bool("json", false, "print the differences as JSON")
+	colorMode := flags.String("color", "auto", "colour output: auto, always or never")
+	flags.Parse(args)
+
+	color, err := useColor(*colorMode, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	var a, b map[string]string
+	var paths []string
+	switch {
+	case *live && flags.NArg() <= 1:
+		spec := *files
+		if flags.NArg() == 1 {
+			spec = flags.Arg(0)
+		}
+		paths = targetFiles(spec)
+		if a, err = dotenv.Read(paths...); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading .env files: %v\n", err)
+			return 2
+		}
+		b = liveValues(a)
+	case !*live && flags.NArg() == 2:
+		aFiles, bFiles := splitFiles(flags.Arg(0)), splitFiles(flags.Arg(1))
+		paths = append(aFiles, bFiles...)
+		if a, err = dotenv.Read(aFiles...); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading .env files: %v\n", err)
+			return 2
+		}
+		if b, err = dotenv.Read(bFiles...); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading .env files: %v\n", err)
+			return 2
+		}
+	default:
+		flags.Usage()
+		return 2
+	}
+
+	// hidden reports whether the values of key must not be printed
+	policy := secretPolicy(paths)
+	hidden := func(key string) bool {
+		return *redact || !*showSecrets && policy.IsSecret(key)
+	}
+
+	diff := dotenv.Diff(a, b)
+	if *asJSON {
+		err = writeDiffJSON(os.Stdout, diff, a, b, hidden)
+	} else {
+		err = writeDiffText(os.Stdout, diff, a, b, hidden, color)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	if diff.Empty() {
+		return 0
+	}
+	return 1
+}
+
+// liveValues returns the current environment restricted to the keys of
+// file, so variables that only exist in the shell are not reported as added
+func liveValues(file map[string]string) map[string]string {
+	result := make(map[string]string)
+	for key := range file {
+		if value, ok := os.LookupEnv(key); ok {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// useColor decides whether to colour output written to f
+func useColor(mode string, f *os.File) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		_, noColor := os.LookupEnv("NO_COLOR")
+		return !noColor && isTerminal(f), nil
+	}
+	return false, fmt.Errorf("unknown color mode %q", mode)
+}
+
+// writeDiffText prints the differences in key order: "+" for added, "-"
+// for removed, and a "-"/"+" pair for changed keys ("~" when the values of
+// the key are hidden)
+func writeDiffText(w io.Writer, diff dotenv.DiffResult, a, b map[string]string, hidden func(string) bool, color bool) error {
+	type line struct {
+		key, sign, value string
+	}
+
+	var lines []line
+	for _, key := range diff.Added {
+		lines = append(lines, line{key, "+", b[key]})
+	}
+	for _, key := range diff.Removed {
+		lines = append(lines, line{key, "-", a[key]})
+	}
+	for _, key := range diff.Changed {
+		if hidden(key) {
+			lines = append(lines, line{key, "~", ""})
+		} else {
+			lines = append(lines, line{key, "-", a[key]}, line{key, "+", b[key]})
+		}
+	}
+
+	// Each key is in exactly one list, so a stable sort keeps the pairs of
+	// changed keys together and in order
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].key < lines[j].key })
+
+	for _, l := range lines {
+		text := l.sign + " " + l.key
+		if !hidden(l.key) {
+			text = l.sign + " " + assignment(l.key, l.value)
+		}
+		if color {
+			text = diffColors[l.sign] + text + colorReset
+		}
+		if _, err := fmt.Fprintln(w, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDiffJSON prints the differences as a diffReport
+func writeDiffJSON(w io.Writer, diff dotenv.DiffResult, a, b map[string]string, hidden func(string) bool) error {
+	value := func(m map[string]string, key string) *string {
+		if hidden(key) {
+			return nil
+		}
+		v := m[key]
+		return &v
+	}
+
+	report := diffReport{
+		Added:   []diffEntry{},
+		Removed: []diffEntry{},
+		Changed: []diffEntry{},
+	}
+	for _, key := range diff.Added {
+		report.Added = append(report.Added, diffEntry{Key: key, Value: value(b, key)})
+	}
+	for _, key := range diff.Removed {
+		report.Removed = append(report.Removed, diffEntry{Key: key, Value: value(a, key)})
+	}
+	for _, key := range diff.Changed {
+		report.Changed = append(report.Changed, diffEntry{Key: key, Old: value(a, key), New: value(b, key)})
+	}
+
+	return writeJSON(w, report)
+}
+
+// assignment formats key and value as a .env line
+func assignment(key, value string) string {
+	line, err := dotenv.Marshal(map[string]string{key: value})
+	if err != nil {
+		return key + "=" + value
+	}
+	return line
+}
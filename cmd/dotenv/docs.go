@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/mew-sh/dotenv"
+)
+
+// Markers delimiting the generated table inside a larger document
+const (
+	docsBegin = "<!-- dotenv docs begin -->"
+	docsEnd   = "<!-- dotenv docs end -->"
+)
+
+func cmdDocs(args []string) int {
+	flags, _ := newFlagSet("docs", "[-schema FILE] [-format markdown|html] [-out FILE [-check]]")
+	schemaFile := flags.String("schema", ".env.example", "annotated example file, or a .json schema")
+	format := flags.String("format", "markdown", "output format: markdown or html")
+	out := flags.String("out", "", "write to FILE between "+docsBegin+" and "+docsEnd+" markers; an existing FILE must contain them")
+	check := flags.Bool("check", false, "with -out, exit 1 instead of writing when FILE is out of date")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 || *check && *out == "" {
+		flags.Usage()
+		return 2
+	}
+
+	schema, err := dotenv.ReadSchema(*schemaFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading schema: %v\n", err)
+		return 2
+	}
+
+	docs, err := schema.Docs(dotenv.DocsFormat(strings.ToLower(*format)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	if *out == "" {
+		fmt.Print(docs)
+		return 0
+	}
+
+	existing, err := os.ReadFile(*out)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", *out, err)
+		return 2
+	}
+	updated, err := spliceDocs(string(existing), docs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", *out, err)
+		return 2
+	}
+
+	if *check {
+		if updated != string(existing) {
+			fmt.Fprintf(os.Stderr, "%s is out of date; run dotenv docs -out %s\n", *out, *out)
+			return 1
+		}
+		return 0
+	}
+
+	if updated != string(existing) {
+		if err := os.WriteFile(*out, []byte(updated), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *out, err)
+			return 2
+		}
+	}
+	return 0
+}
+
+// spliceDocs replaces the text between the docs markers in existing with
+// docs. An empty existing file gets docs between new markers; one with text
+// but no markers is never overwritten.
+func spliceDocs(existing, docs string) (string, error) {
+	if strings.TrimSpace(existing) == "" {
+		return docsBegin + "\n" + docs + docsEnd + "\n", nil
+	}
+
+	begin := strings.Index(existing, docsBegin)
+	end := strings.Index(existing, docsEnd)
+	if begin < 0 || end < begin {
+		return "", fmt.Errorf("file has no %s and %s markers; add them where the reference belongs", docsBegin, docsEnd)
+	}
+
+	head := existing[:begin+len(docsBegin)]
+	return head + "\n" + docs + existing[end:], nil
+}
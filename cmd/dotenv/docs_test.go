@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSpliceDocs(t *testing.T) {
+	docs := "| KEY |\n"
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+		wantErr  bool
+	}{
+		{"new file", "", docsBegin + "\n| KEY |\n" + docsEnd + "\n", false},
+		{"blank file", "\n\n", docsBegin + "\n| KEY |\n" + docsEnd + "\n", false},
+		{
+			"markers",
+			"# App\n\n" + docsBegin + "\nold\n" + docsEnd + "\n\nMore text\n",
+			"# App\n\n" + docsBegin + "\n| KEY |\n" + docsEnd + "\n\nMore text\n",
+			false,
+		},
+		{"no markers", "# App\n\nHand-written text\n", "", true},
+		{"begin marker only", "# App\n" + docsBegin + "\n", "", true},
+		{"markers reversed", docsEnd + "\n" + docsBegin + "\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := spliceDocs(tt.existing, docs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("spliceDocs error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDocsKeepsFileWithoutMarkers(t *testing.T) {
+	dir := t.TempDir()
+	schema := filepath.Join(dir, ".env.example")
+	out := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(schema, []byte("# Port to listen on\nPORT=8080\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	readme := "# App\n\nHand-written text\n"
+	if err := os.WriteFile(out, []byte(readme), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := cmdDocs([]string{"-schema", schema, "-out", out}); code != 2 {
+		t.Errorf("Expected exit code 2, got %d", code)
+	}
+	if data, _ := os.ReadFile(out); string(data) != readme {
+		t.Errorf("Expected %s to be left alone, got:\n%s", out, data)
+	}
+
+	// A new file is written with markers, so it can be updated again
+	generated := filepath.Join(dir, "ENV.md")
+	for range 2 {
+		if code := cmdDocs([]string{"-schema", schema, "-out", generated}); code != 0 {
+			t.Fatalf("Expected exit code 0, got %d", code)
+		}
+	}
+	data, _ := os.ReadFile(generated)
+	if !strings.HasPrefix(string(data), docsBegin+"\n") || strings.Count(string(data), "PORT") != 1 {
+		t.Errorf("Unexpected generated file:\n%s", data)
+	}
+	if code := cmdDocs([]string{"-schema", schema, "-out", generated, "-check"}); code != 0 {
+		t.Errorf("Expected the generated file to be up to date, got exit code %d", code)
+	}
+}
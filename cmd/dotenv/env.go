@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/mew-sh/dotenv"
+)
+
+// cleanAllowlist names the variables passed through from dotenv's own
+// environment when -clean is given
+var cleanAllowlist = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TERM", "LANG", "LC_ALL", "TMPDIR", "TZ",
+	// Windows needs these to start most programs
+	"SYSTEMROOT", "COMSPEC", "PATHEXT", "TEMP", "TMP", "USERPROFILE", "WINDIR",
+}
+
+// envOptions controls how the command's environment is assembled from
+// dotenv's own environment and the values read from .env files
+type envOptions struct {
+	override bool
+	clean    bool
+	keep     stringList
+	set      assignmentList
+	unset    stringList
+	resolve  bool
+	// fileSecrets reads KEY from the file named by a KEY_FILE variable
+	fileSecrets bool
+}
+
+// register adds the environment flags to flags, using the current option
+// values as defaults
+func (o *envOptions) register(flags *flag.FlagSet) {
+	o.keep = slices.Clone(o.keep)
+	o.set = slices.Clone(o.set)
+	o.unset = slices.Clone(o.unset)
+
+	flags.BoolVar(&o.override, "o", o.override, "override existing environment variables")
+	flags.BoolVar(&o.clean, "clean", o.clean, "start the command with only the .env values and allowlisted variables")
+	flags.Var(&o.keep, "keep", "with -clean, also pass KEY through from the environment (repeatable)")
+	flags.Var(&o.set, "e", "set KEY=VALUE, overriding .env files (repeatable)")
+	flags.Var(&o.unset, "unset", "remove KEY from the command's environment (repeatable)")
+	flags.BoolVar(&o.resolve, "resolve", o.resolve, "replace file://, exec:// and other secret references with their values")
+	flags.BoolVar(&o.fileSecrets, "file-secrets", o.fileSecrets, "set KEY from the file named by KEY_FILE in the .env files")
+}
+
+// read reads the .env files, reading KEY_FILE secrets with -file-secrets
+// and resolving secret references with -resolve
+func (o *envOptions) read(files ...string) (map[string]string, error) {
+	vars, err := dotenv.ReadWithOptions(dotenv.Options{FileSecrets: o.fileSecrets}, files...)
+	if err != nil || !o.resolve {
+		return vars, err
+	}
+	return dotenv.Resolve(context.Background(), vars, dotenv.ResolveOptions{})
+}
+
+// build returns the command's environment in os.Environ form
+func (o *envOptions) build(base []string, vars map[string]string) []string {
+	if o.clean {
+		allowed := append(slices.Clone(cleanAllowlist), o.keep...)
+		base = slices.DeleteFunc(slices.Clone(base), func(kv string) bool {
+			key, _, _ := strings.Cut(kv, "=")
+			return !slices.ContainsFunc(allowed, func(k string) bool { return sameKey(k, key) })
+		})
+	}
+
+	env := mergeEnv(base, vars, o.override)
+
+	for _, kv := range o.set {
+		key, _, _ := strings.Cut(kv, "=")
+		env = append(removeEnv(env, key), kv)
+	}
+	for _, key := range o.unset {
+		env = removeEnv(env, key)
+	}
+
+	return env
+}
+
+// mergeEnv combines an environment in os.Environ form with .env values.
+// Existing variables win unless they are empty or override is set, as with
+// dotenv.Load.
+func mergeEnv(base []string, vars map[string]string, override bool) []string {
+	env := make([]string, 0, len(base)+len(vars))
+	present := make(map[string]bool, len(base))
+
+	for _, kv := range base {
+		key, value, _ := strings.Cut(kv, "=")
+		if _, ok := vars[key]; ok && (override || value == "") {
+			continue
+		}
+		present[key] = true
+		env = append(env, kv)
+	}
+
+	for key, value := range vars {
+		if !present[key] {
+			env = append(env, key+"="+value)
+		}
+	}
+
+	return env
+}
+
+// removeEnv removes key from an environment in os.Environ form
+func removeEnv(env []string, key string) []string {
+	return slices.DeleteFunc(env, func(kv string) bool {
+		k, _, _ := strings.Cut(kv, "=")
+		return sameKey(k, key)
+	})
+}
+
+// sameKey compares variable names the way the platform does
+func sameKey(a, b string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// lookPath resolves name using the PATH of the command's environment, so a
+// PATH set in a .env file is honored. dotenv's own PATH is left alone.
+func lookPath(name string, env []string) (string, error) {
+	if strings.ContainsAny(name, `/`+string(filepath.Separator)) {
+		return exec.LookPath(name)
+	}
+
+	path := ""
+	for _, kv := range env {
+		if key, value, _ := strings.Cut(kv, "="); sameKey(key, "PATH") {
+			path = value
+		}
+	}
+
+	for _, dir := range filepath.SplitList(path) {
+		candidate := filepath.Join(dir, name)
+		if !strings.ContainsRune(candidate, filepath.Separator) {
+			// An empty entry or "." means the current directory
+			candidate = "." + string(filepath.Separator) + candidate
+		}
+		// A name with a directory is checked as is, with the executable
+		// extensions tried on Windows
+		file, err := exec.LookPath(candidate)
+		if err != nil {
+			continue
+		}
+		if !filepath.IsAbs(file) {
+			// Refuse relative PATH entries, as exec.LookPath does
+			return "", &exec.Error{Name: name, Err: exec.ErrDot}
+		}
+		return file, nil
+	}
+	return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+}
+
+// stringList is a flag that may be repeated
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// assignmentList is a repeatable KEY=VALUE flag
+type assignmentList []string
+
+func (l *assignmentList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *assignmentList) Set(value string) error {
+	key, _, ok := strings.Cut(value, "=")
+	if !ok || !isValidKey(key) {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
+	}
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestMergeEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     []string
+		vars     map[string]string
+		override bool
+		want     []string
+	}{
+		{"new key", []string{"HOME=/home/u"}, map[string]string{"PORT": "8080"}, false, []string{"HOME=/home/u", "PORT=8080"}},
+		{"existing wins", []string{"PORT=3000"}, map[string]string{"PORT": "8080"}, false, []string{"PORT=3000"}},
+		{"empty is unset", []string{"PORT="}, map[string]string{"PORT": "8080"}, false, []string{"PORT=8080"}},
+		{"override", []string{"PORT=3000"}, map[string]string{"PORT": "8080"}, true, []string{"PORT=8080"}},
+		{"empty file value", []string{"PORT=3000"}, map[string]string{"PORT": ""}, false, []string{"PORT=3000"}},
+		{"override with empty", []string{"PORT=3000"}, map[string]string{"PORT": ""}, true, []string{"PORT="}},
+		{"base without equals", []string{"ODD"}, map[string]string{"ODD": "1"}, false, []string{"ODD=1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeEnv(tt.base, tt.vars, tt.override)
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRemoveEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  []string
+		key  string
+		want []string
+	}{
+		{"present", []string{"A=1", "B=2"}, "A", []string{"B=2"}},
+		{"absent", []string{"A=1"}, "B", []string{"A=1"}},
+		{"duplicates", []string{"A=1", "B=2", "A=3"}, "A", []string{"B=2"}},
+		{"prefix of another key", []string{"AB=1"}, "A", []string{"AB=1"}},
+		{"empty value", []string{"A=", "B=2"}, "A", []string{"B=2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := removeEnv(slices.Clone(tt.env), tt.key); !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEnvOptionsBuild(t *testing.T) {
+	base := []string{"PATH=/usr/bin", "HOME=/home/u", "PORT=3000", "TOKEN=abc", "EMPTY="}
+	vars := map[string]string{"PORT": "8080", "EMPTY": "filled", "DEBUG": "true"}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "defaults",
+			want: []string{"DEBUG=true", "EMPTY=filled", "HOME=/home/u", "PATH=/usr/bin", "PORT=3000", "TOKEN=abc"},
+		},
+		{
+			name: "override",
+			args: []string{"-o"},
+			want: []string{"DEBUG=true", "EMPTY=filled", "HOME=/home/u", "PATH=/usr/bin", "PORT=8080", "TOKEN=abc"},
+		},
+		{
+			name: "clean",
+			args: []string{"-clean"},
+			want: []string{"DEBUG=true", "EMPTY=filled", "HOME=/home/u", "PATH=/usr/bin", "PORT=8080"},
+		},
+		{
+			name: "clean and keep",
+			args: []string{"-clean", "-keep", "TOKEN", "-keep", "PORT"},
+			want: []string{"DEBUG=true", "EMPTY=filled", "HOME=/home/u", "PATH=/usr/bin", "PORT=3000", "TOKEN=abc"},
+		},
+		{
+			name: "set",
+			args: []string{"-e", "PORT=9090", "-e", "NEW=a=b"},
+			want: []string{"DEBUG=true", "EMPTY=filled", "HOME=/home/u", "NEW=a=b", "PATH=/usr/bin", "PORT=9090", "TOKEN=abc"},
+		},
+		{
+			name: "set with clean",
+			args: []string{"-clean", "-e", "TOKEN=xyz"},
+			want: []string{"DEBUG=true", "EMPTY=filled", "HOME=/home/u", "PATH=/usr/bin", "PORT=8080", "TOKEN=xyz"},
+		},
+		{
+			name: "unset",
+			args: []string{"-unset", "TOKEN", "-unset", "DEBUG", "-unset", "MISSING"},
+			want: []string{"EMPTY=filled", "HOME=/home/u", "PATH=/usr/bin", "PORT=3000"},
+		},
+		{
+			name: "unset wins over set",
+			args: []string{"-e", "PORT=9090", "-unset", "PORT"},
+			want: []string{"DEBUG=true", "EMPTY=filled", "HOME=/home/u", "PATH=/usr/bin", "TOKEN=abc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts envOptions
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			opts.register(flags)
+			if err := flags.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			got := opts.build(slices.Clone(base), vars)
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAssignmentFlag(t *testing.T) {
+	tests := []struct {
+		arg     string
+		wantErr bool
+	}{
+		{"KEY=value", false},
+		{"KEY=", false},
+		{"KEY=a=b", false},
+		{"KEY", true},
+		{"=value", true},
+		{"1KEY=value", true},
+		{"MY-KEY=value", true},
+	}
+
+	for _, tt := range tests {
+		var opts envOptions
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		opts.register(flags)
+		if err := flags.Parse([]string{"-e", tt.arg}); (err != nil) != tt.wantErr {
+			t.Errorf("-e %q: error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLookPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the program")
+	}
+
+	dir := t.TempDir()
+	program := filepath.Join(dir, "dotenv-test-program")
+	if err := os.WriteFile(program, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "not-executable"), []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", "/nonexistent")
+
+	tests := []struct {
+		name    string
+		env     []string
+		want    string
+		wantErr bool
+	}{
+		{"dotenv-test-program", []string{"PATH=/nonexistent" + string(filepath.ListSeparator) + dir}, program, false},
+		{"dotenv-test-program", []string{"PATH=/nonexistent"}, "", true},
+		{"dotenv-test-program", nil, "", true},
+		{"not-executable", []string{"PATH=" + dir}, "", true},
+		{program, nil, program, false},
+	}
+
+	for _, tt := range tests {
+		got, err := lookPath(tt.name, tt.env)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("lookPath(%q, %q) = %q, %v; expected %q", tt.name, tt.env, got, err, tt.want)
+		}
+	}
+
+	if path := os.Getenv("PATH"); path != "/nonexistent" {
+		t.Errorf("lookPath changed dotenv's own PATH to %q", path)
+	}
+}
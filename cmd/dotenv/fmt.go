@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mew-sh/dotenv"
+)
+
+func cmdFmt(args []string) int {
+	flags, files := newFlagSet("fmt", "[-w | -check] [-sort] [-export] [FILE...]")
+	write := flags.Bool("w", false, "rewrite files in place instead of printing them")
+	check := flags.Bool("check", false, "list files that are not formatted and exit 1 if there are any")
+	sortKeys := flags.Bool("sort", false, "sort keys within groups separated by blank lines or comments")
+	export := flags.Bool("export", false, "prefix every assignment with export")
+	flags.Parse(args)
+
+	paths := targetFiles(*files)
+	if flags.NArg() > 0 {
+		paths = flags.Args()
+	}
+	opts := dotenv.FormatterOptions{Export: *export, SortKeys: *sortKeys}
+
+	unformatted := false
+	for _, path := range paths {
+		doc, err := dotenv.ReadDocument(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading .env file: %v\n", err)
+			return 2
+		}
+
+		original := doc.String()
+		if err := doc.Format(opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting %s: %v\n", path, err)
+			return 2
+		}
+		formatted := doc.String()
+
+		switch {
+		case *check:
+			if formatted != original {
+				fmt.Println(path)
+				unformatted = true
+			}
+		case *write:
+			if formatted != original {
+				if err := doc.Save(path); err != nil {
+					fmt.Fprintf(os.Stderr, "Error writing .env file: %v\n", err)
+					return 2
+				}
+			}
+		default:
+			fmt.Print(formatted)
+		}
+	}
+
+	if unformatted {
+		return 1
+	}
+	return 0
+}
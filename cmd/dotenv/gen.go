@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mew-sh/dotenv"
+)
+
+func cmdGen(args []string) int {
+	flags, _ := newFlagSet("gen", "[-in FILE] [-out FILE] [-package NAME] [-type NAME] [-file-secrets]")
+	in := flags.String("in", ".env.example", "annotated example file, or a .json schema")
+	out := flags.String("out", "", "write to FILE instead of standard output")
+	pkg := flags.String("package", "", "package name (default $GOPACKAGE, as set by go generate, or the -out directory name)")
+	typeName := flags.String("type", "Config", "name of the generated struct")
+	fileSecrets := flags.Bool("file-secrets", false, "read KEY from the file named by KEY_FILE when it is set")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	if *pkg == "" {
+		*pkg = os.Getenv("GOPACKAGE")
+	}
+	if *pkg == "" && *out != "" {
+		if abs, err := filepath.Abs(*out); err == nil {
+			*pkg = strings.NewReplacer("-", "", ".", "").Replace(filepath.Base(filepath.Dir(abs)))
+		}
+	}
+
+	schema, err := dotenv.ReadSchema(*in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading schema: %v\n", err)
+		return 1
+	}
+
+	src, err := dotenv.GenerateGo(schema, dotenv.GoOptions{
+		Package:     *pkg,
+		TypeName:    *typeName,
+		Source:      filepath.Base(*in),
+		FileSecrets: *fileSecrets,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *out == "" {
+		os.Stdout.Write(src)
+		return 0
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *out, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mew-sh/dotenv"
+)
+
+func cmdInit(args []string) int {
+	var echo func(on bool) error
+	if isTerminal(os.Stdin) {
+		echo = setEcho
+	}
+	return runInit(args, os.Stdin, os.Stderr, echo)
+}
+
+// runInit creates the .env file, reading -values - and answers from stdin
+// and writing prompts and messages to stderr. echo turns echoing of the
+// terminal on and off; it is nil when stdin is not a terminal, and then
+// runInit never prompts.
+func runInit(args []string, stdin io.Reader, stderr io.Writer, echo func(on bool) error) int {
+	flags, files := newFlagSet("init", "[-example FILE] [-f FILE] [-e KEY=VALUE]... [-values FILE] [-non-interactive] [-force]")
+	example := flags.String("example", ".env.example", "example file to start from")
+	var set assignmentList
+	flags.Var(&set, "e", "use VALUE for KEY without prompting (repeatable)")
+	valuesFile := flags.String("values", "", "read values from a .env FILE, or - for standard input")
+	nonInteractive := flags.Bool("non-interactive", false, "never prompt; fail if a required key has no value")
+	force := flags.Bool("force", false, "overwrite an existing file")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	paths := targetFiles(*files)
+	out := paths[len(paths)-1]
+	if _, err := os.Stat(out); err == nil && !*force {
+		fmt.Fprintf(stderr, "Error: %s already exists, use -force to overwrite it\n", out)
+		return 1
+	}
+
+	doc, err := dotenv.ReadDocument(*example)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error reading example file: %v\n", err)
+		return 1
+	}
+	schema, err := dotenv.ReadSchema(*example)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error reading example file: %v\n", err)
+		return 1
+	}
+
+	given, err := readGivenValues(*valuesFile, set, stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error reading values: %v\n", err)
+		return 1
+	}
+
+	// Prompts need both a terminal and a standard input not used for values
+	interactive := !*nonInteractive && *valuesFile != "-" && echo != nil
+	prompter := &prompter{in: bufio.NewReader(stdin), out: stderr, setEcho: echo}
+
+	var missing []string
+	for _, field := range schema.Fields {
+		value, ok := given[field.Key]
+		if ok {
+			delete(given, field.Key)
+		} else {
+			value = field.Default
+			if interactive && (value == "" || field.Required) {
+				if value, err = prompter.ask(field); err != nil {
+					fmt.Fprintf(stderr, "\nError reading input: %v\n", err)
+					return 1
+				}
+			}
+		}
+
+		if field.Required && value == "" {
+			missing = append(missing, field.Key)
+		}
+		if current, _ := doc.Get(field.Key); current != value {
+			doc.Set(field.Key, value)
+		}
+	}
+
+	if len(missing) > 0 {
+		fmt.Fprintf(stderr, "Error: no value for required keys: %s\n", strings.Join(missing, ", "))
+		return 1
+	}
+
+	// Values for keys the example does not mention are appended
+	extra := make([]string, 0, len(given))
+	for key := range given {
+		extra = append(extra, key)
+	}
+	sort.Strings(extra)
+	for _, key := range extra {
+		doc.Set(key, given[key])
+	}
+
+	if err := doc.Save(out); err != nil {
+		fmt.Fprintf(stderr, "Error writing .env file: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "Wrote %s\n", out)
+	return 0
+}
+
+// readGivenValues merges the values read from file ("-" for stdin) with the
+// -e assignments, which take precedence
+func readGivenValues(file string, set assignmentList, stdin io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+
+	switch file {
+	case "":
+	case "-":
+		env, err := dotenv.Parse(stdin)
+		if err != nil {
+			return nil, err
+		}
+		values = env
+	default:
+		env, err := dotenv.Read(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s does not exist", file)
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = env
+	}
+
+	for _, assignment := range set {
+		key, value, _ := strings.Cut(assignment, "=")
+		values[key] = value
+	}
+	return values, nil
+}
+
+// prompter asks for values on the terminal
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	// setEcho turns echoing on and off to hide the input of secrets
+	setEcho func(on bool) error
+}
+
+// ask prompts for the value of field until it gets a valid one, hiding the
+// input of secrets. An empty answer keeps the default.
+func (p *prompter) ask(field dotenv.Field) (string, error) {
+	if field.Description != "" {
+		fmt.Fprintf(p.out, "\n# %s\n", field.Description)
+	}
+
+	label := field.Key
+	switch {
+	case field.IsSecret() && field.Default != "":
+		label += " [keep default]"
+	case field.Default != "":
+		label += " [" + field.Default + "]"
+	case field.Example != "":
+		label += " (e.g. " + field.Example + ")"
+	}
+	if len(field.Enum) > 0 {
+		label += " {" + strings.Join(field.Enum, ",") + "}"
+	}
+
+	for {
+		fmt.Fprintf(p.out, "%s: ", label)
+
+		var line string
+		var err error
+		if field.IsSecret() && p.setEcho(false) == nil {
+			line, err = p.in.ReadString('\n')
+			p.setEcho(true)
+			fmt.Fprintln(p.out)
+		} else {
+			line, err = p.in.ReadString('\n')
+		}
+		if err != nil && (err != io.EOF || line == "") {
+			return "", err
+		}
+
+		value := strings.TrimRight(line, "\r\n")
+		if value == "" {
+			value = field.Default
+		}
+
+		schema := dotenv.Schema{Fields: []dotenv.Field{field}}
+		var verr *dotenv.ValidationError
+		if !errors.As(schema.Validate(map[string]string{field.Key: value}), &verr) {
+			return value, nil
+		}
+		fmt.Fprintln(p.out, verr.Problems[0])
+	}
+}
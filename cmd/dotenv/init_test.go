@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mew-sh/dotenv"
+)
+
+const initExample = `# Port the HTTP server listens on
+# @type int @required
+PORT=8080
+
+# @required @secret
+API_KEY=
+
+# @enum debug,info
+LOG_LEVEL=
+
+NAME=app
+`
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		terminal bool
+		existing string
+		code     int
+		want     map[string]string // nil if no file may be written
+		stderr   string
+		echo     []bool
+	}{
+		{
+			name:     "prompts",
+			stdin:    "\nsecret123\nverbose\ninfo\n",
+			terminal: true,
+			want:     map[string]string{"PORT": "8080", "API_KEY": "secret123", "LOG_LEVEL": "info", "NAME": "app"},
+			stderr:   "# Port the HTTP server listens on\nPORT [8080]: API_KEY: \nLOG_LEVEL {debug,info}: LOG_LEVEL must be one of debug, info\nLOG_LEVEL {debug,info}: Wrote",
+			echo:     []bool{false, true},
+		},
+		{
+			name:     "prompt for invalid value",
+			stdin:    "http\n443\nk\n\n",
+			terminal: true,
+			want:     map[string]string{"PORT": "443", "API_KEY": "k", "LOG_LEVEL": "", "NAME": "app"},
+			stderr:   "must be an integer",
+			echo:     []bool{false, true},
+		},
+		{
+			name:     "values from stdin",
+			args:     []string{"-values", "-"},
+			stdin:    "API_KEY=from-stdin\nLOG_LEVEL=debug\nEXTRA=1\n",
+			terminal: true,
+			want:     map[string]string{"PORT": "8080", "API_KEY": "from-stdin", "LOG_LEVEL": "debug", "NAME": "app", "EXTRA": "1"},
+		},
+		{
+			name:  "-e overrides values",
+			args:  []string{"-values", "-", "-e", "API_KEY=from-flag", "-e", "NAME=svc"},
+			stdin: "API_KEY=from-stdin\n",
+			want:  map[string]string{"PORT": "8080", "API_KEY": "from-flag", "LOG_LEVEL": "", "NAME": "svc"},
+		},
+		{
+			name:     "non-interactive with missing required key",
+			args:     []string{"-non-interactive"},
+			stdin:    "never read\n",
+			terminal: true,
+			code:     1,
+			stderr:   "no value for required keys: API_KEY",
+		},
+		{
+			name:   "no terminal with missing required key",
+			code:   1,
+			stderr: "no value for required keys: API_KEY",
+		},
+		{
+			name:     "input ends while prompting",
+			stdin:    "\n",
+			terminal: true,
+			code:     1,
+			stderr:   "Error reading input: EOF",
+			echo:     []bool{false, true},
+		},
+		{
+			name:     "refuses to overwrite",
+			args:     []string{"-e", "API_KEY=x"},
+			existing: "KEEP=1\n",
+			code:     1,
+			stderr:   "already exists, use -force",
+		},
+		{
+			name:     "-force overwrites",
+			args:     []string{"-force", "-e", "API_KEY=x"},
+			existing: "KEEP=1\n",
+			want:     map[string]string{"PORT": "8080", "API_KEY": "x", "LOG_LEVEL": "", "NAME": "app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			example := filepath.Join(dir, ".env.example")
+			out := filepath.Join(dir, ".env")
+			if err := os.WriteFile(example, []byte(initExample), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.existing != "" {
+				if err := os.WriteFile(out, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var echoed []bool
+			var echo func(on bool) error
+			if tt.terminal {
+				echo = func(on bool) error {
+					echoed = append(echoed, on)
+					return nil
+				}
+			}
+
+			var stderr bytes.Buffer
+			args := append([]string{"-example", example, "-f", out}, tt.args...)
+			if code := runInit(args, strings.NewReader(tt.stdin), &stderr, echo); code != tt.code {
+				t.Fatalf("Expected exit code %d, got %d: %s", tt.code, code, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("Expected output containing %q, got:\n%s", tt.stderr, stderr.String())
+			}
+			if !slices.Equal(echoed, tt.echo) {
+				t.Errorf("Expected echo calls %v, got %v", tt.echo, echoed)
+			}
+
+			if tt.want == nil {
+				data, err := os.ReadFile(out)
+				if tt.existing == "" && err == nil {
+					t.Errorf("Expected no file to be written, got:\n%s", data)
+				}
+				if tt.existing != "" && string(data) != tt.existing {
+					t.Errorf("Expected %s to be left alone, got:\n%s", out, data)
+				}
+				return
+			}
+			env, err := dotenv.Read(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !maps.Equal(env, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, env)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/mew-sh/dotenv"
+)
+
+func cmdLint(args []string) int {
+	flags, files := newFlagSet("lint", "[-f FILES] [-example FILE] [-disable RULES] [-fix] [-format text|json|sarif] [FILE...]")
+	example := flags.String("example", "", "reference file for the not-in-example rule (default .env.example next to each file, if present)")
+	disable := flags.String("disable", "", "comma separated rules to skip")
+	fix := flags.Bool("fix", false, "rewrite files, fixing problems that are safe to fix")
+	format := flags.String("format", "text", "output format: text, json or sarif")
+	listRules := flags.Bool("rules", false, "list the available rules and exit")
+	flags.Parse(args)
+
+	if *listRules {
+		for _, rule := range dotenv.LintRules() {
+			fixable := ""
+			if rule.Fixable {
+				fixable = " (fixable)"
+			}
+			fmt.Printf("%-20s %s%s\n", rule.Name, rule.Description, fixable)
+		}
+		return 0
+	}
+
+	if *format != "text" && *format != "json" && *format != "sarif" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q\n", *format)
+		return 2
+	}
+
+	paths := targetFiles(*files)
+	if flags.NArg() > 0 {
+		paths = flags.Args()
+	}
+
+	var diags []dotenv.Diagnostic
+	for _, path := range paths {
+		opts := dotenv.LintOptions{Disable: splitFiles(*disable)}
+		examplePath := *example
+		if examplePath == "" {
+			examplePath = filepath.Join(filepath.Dir(path), ".env.example")
+			if filepath.Clean(path) == examplePath {
+				examplePath = ""
+			}
+		}
+		if examplePath != "" {
+			env, err := dotenv.Read(examplePath)
+			switch {
+			case err == nil:
+				opts.Example = env
+			case *example != "" || !errors.Is(err, fs.ErrNotExist):
+				fmt.Fprintf(os.Stderr, "Error reading example file: %v\n", err)
+				return 2
+			}
+		}
+
+		found, err := lintFile(path, opts, *fix)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		diags = append(diags, found...)
+	}
+
+	var err error
+	switch *format {
+	case "json":
+		err = writeJSON(os.Stdout, diags)
+	case "sarif":
+		err = writeSARIF(os.Stdout, diags)
+	default:
+		for _, d := range diags {
+			fmt.Println(d)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	if len(diags) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// lintFile lints one file, first fixing it in place when fix is set
+func lintFile(path string, opts dotenv.LintOptions, fix bool) ([]dotenv.Diagnostic, error) {
+	doc, err := dotenv.ReadDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if fix {
+		fixed, err := dotenv.FixLint(doc, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if fixed > 0 {
+			if err := doc.Save(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	diags, err := dotenv.Lint(doc, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range diags {
+		diags[i].File = path
+	}
+	return diags, nil
+}
+
+// writeJSON prints v as indented JSON
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Minimal SARIF 2.1.0 types, enough for code scanning annotations
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name  string      `json:"name"`
+		Rules []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifLocation struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region struct {
+				StartLine int `json:"startLine"`
+			} `json:"region"`
+		} `json:"physicalLocation"`
+	}
+)
+
+// writeSARIF prints diags as a SARIF log
+func writeSARIF(w io.Writer, diags []dotenv.Diagnostic) error {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "dotenv"}},
+		Results: []sarifResult{},
+	}
+	for _, rule := range dotenv.LintRules() {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               rule.Name,
+			ShortDescription: sarifMessage{Text: rule.Description},
+		})
+	}
+
+	for _, d := range diags {
+		var loc sarifLocation
+		loc.PhysicalLocation.ArtifactLocation.URI = filepath.ToSlash(d.File)
+		loc.PhysicalLocation.Region.StartLine = d.Line
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    d.Rule,
+			Level:     "warning",
+			Message:   sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{loc},
+		})
+	}
+
+	return writeJSON(w, sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -5,22 +5,25 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
-	"syscall"
-
-	"github.com/mew-sh/dotenv"
 )
 
 var (
 	envFiles    = flag.String("f", "", "comma separated paths to .env files")
-	overload    = flag.Bool("o", false, "override existing environment variables")
+	supervised  = flag.Bool("s", false, "run the command as a supervised child process")
 	showHelp    = flag.Bool("h", false, "show help")
 	showVersion = flag.Bool("v", false, "show version")
+
+	// childEnv holds the flags controlling the command's environment
+	childEnv envOptions
 )
 
 const version = "v2.0.0"
 
+func init() {
+	childEnv.register(flag.CommandLine)
+}
+
 func main() {
 	flag.Parse()
 
@@ -34,27 +37,17 @@ func main() {
 		return
 	}
 
-	// Load environment files
-	var files []string
-	if *envFiles != "" {
-		files = strings.Split(*envFiles, ",")
-		// Trim whitespace from file names
-		for i, file := range files {
-			files[i] = strings.TrimSpace(file)
-		}
-	}
-
-	var err error
-	if *overload {
-		err = dotenv.Overload(files...)
-	} else {
-		err = dotenv.Load(files...)
+	if run, ok := subcommand(flag.Arg(0), afterTerminator()); ok {
+		os.Exit(run(flag.Args()[1:]))
 	}
 
+	// Load environment files
+	vars, err := childEnv.read(splitFiles(*envFiles)...)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading .env files: %v\n", err)
 		os.Exit(1)
 	}
+	env := childEnv.build(os.Environ(), vars)
 
 	// Execute the command
 	args := flag.Args()
@@ -62,32 +55,154 @@ func main() {
 	cmdArgs := args[1:]
 
 	// Look for the command in PATH
-	cmdPath, err := exec.LookPath(cmd)
+	cmdPath, err := lookPath(cmd, env)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Command not found: %s\n", cmd)
 		os.Exit(127)
 	}
 
+	argv := append([]string{cmd}, cmdArgs...)
+
+	// Supervise the command when asked to, or when the platform cannot
+	// replace the current process
+	if *supervised || !canExec {
+		state, err := supervise(cmdPath, argv, env)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to execute command: %v\n", err)
+			os.Exit(1)
+		}
+		exitWithState(state)
+	}
+
 	// Execute the command with the loaded environment
-	err = syscall.Exec(cmdPath, append([]string{cmd}, cmdArgs...), os.Environ())
+	err = execProcess(cmdPath, argv, env)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to execute command: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// subcommand returns the subcommand called name. Subcommand names always
+// mean the subcommand, even if a program of the same name is on PATH, unless
+// they follow a "--" terminator.
+func subcommand(name string, terminated bool) (func(args []string) int, bool) {
+	run, ok := commands[name]
+	return run, ok && !terminated
+}
+
+// afterTerminator reports whether the positional arguments were preceded by
+// a "--" flag terminator
+func afterTerminator() bool {
+	i := len(os.Args) - flag.NArg() - 1
+	return i > 0 && os.Args[i] == "--"
+}
+
+// splitFiles splits a comma separated -f value into file names
+func splitFiles(spec string) []string {
+	if spec == "" {
+		return nil
+	}
+
+	files := strings.Split(spec, ",")
+	// Trim whitespace from file names
+	for i, file := range files {
+		files[i] = strings.TrimSpace(file)
+	}
+	return files
+}
+
 func showUsage() {
 	fmt.Printf(`dotenv %s - Load environment variables from .env files and execute commands
 
 Usage:
   dotenv [options] COMMAND [ARGS...]
+  dotenv [options] SUBCOMMAND [ARGS...]
+  dotenv [options] -- COMMAND [ARGS...]
 
 Options:
   -f FILE       comma separated paths to .env files (default: .env)
   -o            override existing environment variables
+  -e KEY=VALUE  set a variable for the command, overriding .env files (repeatable)
+  -unset KEY    remove a variable from the command's environment (repeatable)
+  -clean        start the command with only the .env values and -e variables plus
+                PATH, HOME, USER, LOGNAME, SHELL, TERM, LANG, LC_ALL, TMPDIR and TZ
+  -keep KEY     with -clean, also pass KEY through from the environment (repeatable)
+  -resolve      replace secret references such as file:///run/secrets/db and
+                exec://pass show db with the file contents or command output
+  -file-secrets set KEY to the contents of the file named by a KEY_FILE
+                variable in the .env files (Docker/Kubernetes secrets)
+  -s            supervise the command as a child process instead of replacing
+                dotenv: signals are forwarded, the exit code or terminating
+                signal is passed through and the child's process group is
+                cleaned up (always on for platforms without exec)
   -h            show this help message
   -v            show version
 
+Subcommands:
+  get KEY               print the value of KEY
+  set KEY=VALUE...      set keys in the last -f file, keeping comments and order
+  unset KEY...          remove keys from every -f file
+  list                  print all variables in .env format, with secret
+                        values redacted unless -show-secrets is given
+  keys                  print all variable names
+  export [-shell NAME]  print shell statements setting the variables, for
+                        bash, zsh, fish, powershell, cmd or nushell
+  convert -to FORMAT    print the variables as dotenv, json, yaml, toml,
+                        docker, shell, systemd, properties or ini (-out FILE
+                        to write a file); -from json flattens nested JSON into
+                        KEY_SUBKEY names, -from properties and -from ini read
+                        Java .properties and INI files
+  diff A B              compare two files (or comma separated lists of files),
+                        printing added (+), removed (-) and changed keys;
+                        -env compares the -f files with the current
+                        environment, -redact hides values, -json prints JSON;
+                        values of secret keys are hidden unless -show-secrets
+  lint [FILE...]        report duplicate keys, lower-case keys, trailing
+                        whitespace, unquoted spaces, undefined variables and
+                        keys missing from .env.example; -fix rewrites what is
+                        safe to fix, -format json|sarif for CI, -rules lists
+                        the rules and -disable RULES skips some
+  fmt [FILE...]         print files in canonical form; -w rewrites them,
+                        -check lists unformatted files, -sort sorts keys
+                        within groups and -export adds export prefixes
+  check                 validate the -f files against the annotated
+                        .env.example (-schema FILE, or a .json schema),
+                        reporting missing required keys, unknown keys, type
+                        mismatches and pattern failures
+  init                  create the last -f file (.env) from .env.example,
+                        prompting for empty and required values (secrets
+                        are not echoed); -e KEY=VALUE and -values FILE|-
+                        supply values, -non-interactive never prompts
+  docs                  print a Markdown (or -format html) reference of the
+                        keys in .env.example; -out FILE writes it between
+                        <!-- dotenv docs begin/end --> markers (an existing
+                        FILE must have them), and -check exits 1 when FILE
+                        is out of date
+  gen                   generate a Go struct with typed fields and a Load
+                        function from .env.example (-in FILE, -out FILE,
+                        -package NAME, -type NAME, -file-secrets); for
+                        //go:generate
+  encrypt [KEY...]      encrypt values (all by default) in the -f files as
+                        enc:v1:..., creating .env.key if no key is set
+  decrypt [KEY...]      turn encrypted values back into plain text
+  scan [FILE...]        report values that look like real credentials: AWS
+                        keys, GitHub tokens, private keys, JWTs and
+                        high-entropy strings; -allow FILE (default
+                        .dotenv-allowlist) lists accepted keys and values
+  run [flags] COMMAND   run COMMAND as a supervised child process; accepts
+                        -o, -e, -unset, -clean, -keep, -resolve and
+                        -file-secrets as well as:
+      --watch             restart COMMAND when the -f files change; a file
+                          with a parse error is reported and ignored
+      --stop-signal SIG   signal used to stop COMMAND before a restart (TERM)
+      --grace DURATION    time to wait before killing COMMAND (10s)
+      --interval DURATION how often to check the files (500ms)
+
+  Subcommands also accept -f after their name, e.g. dotenv get -f .env.local KEY.
+  Subcommand names always run the subcommand, even when a program of the
+  same name (such as diff, fmt or init) is on PATH; use "dotenv -- diff" to
+  run the program.
+
 Examples:
   # Load .env and run a command
   dotenv go run main.go
@@ -98,18 +213,66 @@ Examples:
   # Override existing environment variables
   dotenv -o -f .env.override python app.py
 
+  # Reproduce a CI-like environment locally
+  dotenv -clean -f .env.ci -e LOG_LEVEL=debug -unset HTTP_PROXY make test
+
+  # Keep dotenv running as the parent of the command
+  dotenv -s node server.js
+
   # Load from multiple files (later files take precedence)
   dotenv -f .env,.env.local,.env.development rails server
 
+  # Load the variables into the current shell
+  eval "$(dotenv -f .env.dev export)"
+
+  # Feed the same configuration to other tools
+  dotenv -f .env.production convert -to docker -out prod.env
+
+  # Flatten a nested JSON secrets export into a .env file
+  secrets-export | dotenv convert -from json -f - -to dotenv -out .env
+
+  # Restart a development server whenever .env changes
+  dotenv run --watch --stop-signal INT go run ./cmd/server
+
+  # Review what a deploy would change, without printing secrets
+  dotenv diff -redact .env.staging .env.production
+
+  # Annotate pull requests with problems in .env files
+  dotenv lint -format sarif .env.example > dotenv.sarif
+
+  # Keep real credentials out of committed files (pre-commit hook)
+  dotenv scan .env.example
+
+  # Set up a new checkout
+  dotenv init
+  dotenv init -non-interactive -e API_KEY="$CI_API_KEY"
+
+  # Inspect and edit files
+  dotenv -f .env.production get DATABASE_URL
+  dotenv set PORT=8080 DEBUG=false
+
 Environment Files:
   If no -f flag is provided, dotenv will attempt to load .env from the current directory.
   Multiple files can be specified with comma separation.
   Files are loaded in order, with later files taking precedence for duplicate keys.
 
+Encryption:
+  Encrypted values are decrypted when the files are loaded if a key is
+  available: DOTENV_PRIVATE_KEY (64 hex digits), or the file named by
+  DOTENV_KEY_FILE (default .env.key). Without a key they are left as is.
+
+Secrets:
+  Values of keys such as *_SECRET, *_PASSWORD, *_TOKEN and *_API_KEY, and of
+  keys marked @secret in the .env.example next to the files, are printed as
+  [redacted] in reports and error messages.
+
 Exit Codes:
   0    Command executed successfully
-  1    Error loading .env files or executing command
+  1    Error loading .env files or executing command, or key not found (get)
   127  Command not found
+  diff, lint, check, scan, fmt -check and docs -check exit 1 when they report differences or problems
+  and 2 on errors
+  With -s, the command's own exit code; 128+N if it was killed by signal N
 
 For more information, visit: https://github.com/mew-sh/dotenv
 `, version)
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSubcommandDispatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the shadowing program")
+	}
+
+	// Programs on PATH with the names of subcommands do not shadow them
+	dir := t.TempDir()
+	for _, name := range []string{"diff", "fmt", "init"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+
+	tests := []struct {
+		name       string
+		terminated bool
+		want       bool
+	}{
+		{"diff", false, true},
+		{"fmt", false, true},
+		{"init", false, true},
+		{"lint", false, true},
+		{"diff", true, false}, // "dotenv -- diff" runs the program
+		{"nosuchcommand", false, false},
+	}
+	for _, tt := range tests {
+		if _, ok := subcommand(tt.name, tt.terminated); ok != tt.want {
+			t.Errorf("subcommand(%q, %t) = %t, expected %t", tt.name, tt.terminated, ok, tt.want)
+		}
+	}
+}
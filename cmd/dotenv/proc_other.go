@@ -0,0 +1,69 @@
+//go:build !unix
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// canExec reports whether dotenv can replace itself with the command
+const canExec = false
+
+// terminateSignal asks processes to exit; platforms without Unix signals
+// can only kill
+var terminateSignal os.Signal = os.Kill
+
+// forwardedSignals are relayed from dotenv to a supervised child
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+// parseSignal parses a signal name; only INT and KILL can be delivered on
+// this platform
+func parseSignal(name string) (os.Signal, error) {
+	switch strings.TrimPrefix(strings.ToUpper(name), "SIG") {
+	case "INT":
+		return os.Interrupt, nil
+	case "KILL", "TERM":
+		return os.Kill, nil
+	}
+	return nil, fmt.Errorf("unknown signal %q", name)
+}
+
+// execProcess is not available on this platform
+func execProcess(path string, args, env []string) error {
+	return errors.New("replacing the process is not supported on this platform")
+}
+
+// setProcessGroup is a no-op; process groups are a Unix concept
+func setProcessGroup(cmd *exec.Cmd) bool {
+	return false
+}
+
+// signalProcess sends sig to p
+func signalProcess(p *os.Process, sig os.Signal, group bool) error {
+	return p.Signal(sig)
+}
+
+// isTerminalSignal reports whether the console delivers sig to every
+// attached process (Ctrl-C)
+func isTerminalSignal(sig os.Signal) bool {
+	return sig == os.Interrupt
+}
+
+// exitWithState exits dotenv with the child's exit code
+func exitWithState(state *os.ProcessState) {
+	os.Exit(exitCode(state))
+}
+
+// exitCode returns the child's exit code
+func exitCode(state *os.ProcessState) int {
+	return state.ExitCode()
+}
+
+// setEcho cannot hide input on this platform
+func setEcho(on bool) error {
+	return errors.New("hiding input is not supported on this platform")
+}
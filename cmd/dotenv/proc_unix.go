@@ -0,0 +1,122 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// canExec reports whether dotenv can replace itself with the command
+const canExec = true
+
+// terminateSignal asks processes to exit gracefully
+var terminateSignal os.Signal = syscall.SIGTERM
+
+// forwardedSignals are relayed from dotenv to a supervised child
+var forwardedSignals = []os.Signal{
+	syscall.SIGINT,
+	syscall.SIGTERM,
+	syscall.SIGHUP,
+	syscall.SIGQUIT,
+	syscall.SIGUSR1,
+	syscall.SIGUSR2,
+	syscall.SIGWINCH,
+}
+
+// signalNames maps the signal names accepted on the command line
+var signalNames = map[string]syscall.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"KILL": syscall.SIGKILL,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+	"TERM": syscall.SIGTERM,
+}
+
+// parseSignal parses a signal name such as TERM or SIGTERM, or a number
+func parseSignal(name string) (os.Signal, error) {
+	if n, err := strconv.Atoi(name); err == nil && n > 0 {
+		return syscall.Signal(n), nil
+	}
+
+	if sig, ok := signalNames[strings.TrimPrefix(strings.ToUpper(name), "SIG")]; ok {
+		return sig, nil
+	}
+	return nil, fmt.Errorf("unknown signal %q", name)
+}
+
+// execProcess replaces the dotenv process with the command
+func execProcess(path string, args, env []string) error {
+	return syscall.Exec(path, args, env)
+}
+
+// setProcessGroup arranges for cmd to start in a new process group
+func setProcessGroup(cmd *exec.Cmd) bool {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return true
+}
+
+// signalProcess sends sig to p, or to the process group it leads
+func signalProcess(p *os.Process, sig os.Signal, group bool) error {
+	if !group {
+		return p.Signal(sig)
+	}
+
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return p.Signal(sig)
+	}
+
+	err := syscall.Kill(-p.Pid, s)
+	if errors.Is(err, syscall.ESRCH) {
+		return os.ErrProcessDone
+	}
+	return err
+}
+
+// isTerminalSignal reports whether the terminal sends sig to its whole
+// foreground process group (Ctrl-C, Ctrl-\)
+func isTerminalSignal(sig os.Signal) bool {
+	return sig == syscall.SIGINT || sig == syscall.SIGQUIT
+}
+
+// exitWithState exits dotenv the same way the child exited. A child killed
+// by a signal is mirrored by dotenv killing itself with that signal, so
+// shells and supervisors further up observe the same outcome.
+func exitWithState(state *os.ProcessState) {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		sig := status.Signal()
+		signal.Reset(sig)
+		syscall.Kill(os.Getpid(), sig)
+	}
+
+	os.Exit(exitCode(state))
+}
+
+// exitCode returns the status a shell reports for a child that ended in
+// state: its exit code, or 128+N when it was killed by signal N
+func exitCode(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return state.ExitCode()
+}
+
+// setEcho turns echoing of typed characters on the terminal on or off
+func setEcho(on bool) error {
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+	cmd := exec.Command("stty", mode)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"time"
+)
+
+func cmdRun(args []string) int {
+	flags, files := newFlagSet("run", "[-f FILES] [-o] [--clean] [-e KEY=VALUE] [--unset KEY] [--resolve] [--file-secrets] [--watch] [--stop-signal SIG] [--grace DURATION] COMMAND [ARGS...]")
+	opts := childEnv
+	opts.register(flags)
+	watch := flags.Bool("watch", false, "restart the command when the .env files change")
+	stopSignal := flags.String("stop-signal", "TERM", "signal used to stop the command before a restart")
+	grace := flags.Duration("grace", 10*time.Second, "time to wait for the command to stop before killing it")
+	interval := flags.Duration("interval", 500*time.Millisecond, "how often to check the .env files for changes")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	sig, err := parseSignal(*stopSignal)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid stop signal: %v\n", err)
+		return 2
+	}
+
+	paths := splitFiles(*files)
+	vars, err := opts.read(paths...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading .env files: %v\n", err)
+		return 1
+	}
+
+	w := &watcher{
+		argv:  flags.Args(),
+		base:  os.Environ(),
+		opts:  &opts,
+		files: targetFiles(*files),
+		vars:  vars,
+	}
+
+	cmdPath, err := lookPath(w.argv[0], w.env())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Command not found: %s\n", w.argv[0])
+		return 127
+	}
+	w.path = cmdPath
+
+	var state *os.ProcessState
+	if *watch {
+		state, err = w.run(sig, *grace, *interval)
+	} else {
+		state, err = supervise(cmdPath, w.argv, w.env())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to execute command: %v\n", err)
+		return 1
+	}
+	if state == nil {
+		// Stopped while waiting for changes after the command had exited
+		return 0
+	}
+
+	exitWithState(state)
+	return 0
+}
+
+// watcher runs a command and restarts it whenever the values in its .env
+// files change
+type watcher struct {
+	path  string
+	argv  []string
+	base  []string
+	opts  *envOptions
+	files []string
+	// vars holds the values the running command was started with
+	vars map[string]string
+	// contents holds the last seen content of each file
+	contents [][]byte
+}
+
+// env returns the environment for the command: the environment dotenv was
+// started with combined with the current .env values
+func (w *watcher) env() []string {
+	return w.opts.build(w.base, w.vars)
+}
+
+// run starts the command and supervises it until it exits after dotenv
+// itself was asked to stop. A command that exits on its own is restarted on
+// the next change. The returned state is nil if dotenv was stopped while no
+// command was running.
+func (w *watcher) run(stop os.Signal, grace, interval time.Duration) (*os.ProcessState, error) {
+	signals := make(chan os.Signal, 8)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+
+	w.contents = readContents(w.files)
+
+	c, err := startChild(w.path, w.argv, w.env())
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	stopping := false
+	for {
+		var done chan struct{}
+		if c != nil {
+			done = c.done
+		}
+
+		select {
+		case sig := <-signals:
+			if c == nil {
+				// Nothing is running, so there is nothing to wait for;
+				// other signals such as SIGWINCH are ignored
+				if sig == os.Interrupt || sig == terminateSignal {
+					return nil, nil
+				}
+				continue
+			}
+			if isTerminalSignal(sig) || sig == terminateSignal {
+				stopping = true
+			}
+			c.forward(sig)
+
+		case <-done:
+			c.cleanup()
+			if stopping {
+				return c.state, nil
+			}
+			fmt.Fprintf(os.Stderr, "dotenv: command exited (%v), waiting for changes\n", c.state)
+			c = nil
+
+		case <-ticker.C:
+			vars, changed, err := w.poll()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "dotenv: not restarting: %v\n", err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+
+			w.vars = vars
+			fmt.Fprintln(os.Stderr, "dotenv: .env files changed, restarting")
+			if c != nil {
+				c.stop(stop, grace)
+			}
+
+			c, err = startChild(w.path, w.argv, w.env())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "dotenv: failed to restart command: %v\n", err)
+				c = nil
+			}
+		}
+	}
+}
+
+// poll checks the watched files and re-parses them when their content has
+// changed. It reports whether the resulting values differ from the ones the
+// command is running with; edits that only touch comments or formatting do
+// not cause a restart.
+func (w *watcher) poll() (map[string]string, bool, error) {
+	contents := readContents(w.files)
+
+	modified := false
+	for i := range contents {
+		if !bytes.Equal(contents[i], w.contents[i]) {
+			modified = true
+		}
+	}
+	if !modified {
+		return nil, false, nil
+	}
+	w.contents = contents
+
+	vars, err := w.opts.read(w.files...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return vars, !maps.Equal(vars, w.vars), nil
+}
+
+// readContents reads each file, treating unreadable files as empty
+func readContents(files []string) [][]byte {
+	contents := make([][]byte, len(files))
+	for i, file := range files {
+		contents[i], _ = os.ReadFile(file)
+	}
+	return contents
+}
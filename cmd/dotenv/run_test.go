@@ -0,0 +1,173 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// newTestWatcher returns a watcher running the helper process in mode with
+// the values of envFile; the helper reports to out
+func newTestWatcher(t *testing.T, mode, envFile, out string) *watcher {
+	t.Helper()
+	opts := &envOptions{}
+	vars, err := opts.read(envFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, argv, env := helperCommand(mode, out)
+	env = slices.DeleteFunc(env, func(kv string) bool { return strings.HasPrefix(kv, "VALUE=") })
+	return &watcher{path: path, argv: argv, base: env, opts: opts, files: []string{envFile}, vars: vars}
+}
+
+// writeEnv replaces the content of file atomically, so the watcher never
+// sees it truncated
+func writeEnv(t *testing.T, file, content string) {
+	t.Helper()
+	if err := os.WriteFile(file+".tmp", []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(file+".tmp", file); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcherPoll(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, ".env")
+	writeEnv(t, file, "VALUE=one\n")
+
+	w := newTestWatcher(t, "sleep", file, filepath.Join(dir, "out"))
+	w.contents = readContents(w.files)
+
+	tests := []struct {
+		name    string
+		content string
+		changed bool
+		value   string
+		wantErr bool
+	}{
+		{"unchanged", "VALUE=one\n", false, "", false},
+		{"comment only", "# the value\nVALUE=one # still one\n", false, "", false},
+		{"value changed", "VALUE=two\n", true, "two", false},
+		{"parse error", "VALUE two\n", false, "", true},
+		{"fixed", "VALUE=three\n", true, "three", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeEnv(t, file, tt.content)
+			vars, changed, err := w.poll()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("poll error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if changed != tt.changed {
+				t.Fatalf("Expected changed=%v, got %v", tt.changed, changed)
+			}
+			if changed {
+				if vars["VALUE"] != tt.value {
+					t.Errorf("Expected VALUE=%s, got %q", tt.value, vars["VALUE"])
+				}
+				w.vars = vars
+			}
+		})
+	}
+}
+
+func TestWatcherRestartsOnChange(t *testing.T) {
+	withoutTerminal(t)
+	dir := t.TempDir()
+	file := filepath.Join(dir, ".env")
+	out := filepath.Join(dir, "out")
+	writeEnv(t, file, "VALUE=one\n")
+
+	w := newTestWatcher(t, "sleep", file, out)
+	type result struct {
+		state *os.ProcessState
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		state, err := w.run(syscall.SIGTERM, 5*time.Second, 10*time.Millisecond)
+		done <- result{state, err}
+	}()
+
+	waitLines(t, out, 1)
+
+	// Comments do not change the values, and a file that fails to parse
+	// keeps the command running with the values it has
+	writeEnv(t, file, "# comment\nVALUE=one\n")
+	time.Sleep(100 * time.Millisecond)
+	writeEnv(t, file, "VALUE two\n")
+	time.Sleep(100 * time.Millisecond)
+
+	writeEnv(t, file, "VALUE=two\n")
+	lines := waitLines(t, out, 2)
+	time.Sleep(100 * time.Millisecond)
+	if got, _ := os.ReadFile(out); string(got) != "one\ntwo\n" {
+		t.Fatalf("Expected the command to be started with one, then two; got %q (%q)", got, lines)
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatalf("run failed: %v", r.err)
+		}
+		if r.state == nil || exitCode(r.state) != 128+int(syscall.SIGTERM) {
+			t.Errorf("Expected the command to be stopped by SIGTERM, got %v", r.state)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("run did not stop on SIGTERM")
+	}
+}
+
+func TestWatcherIgnoresSignalsWithoutCommand(t *testing.T) {
+	withoutTerminal(t)
+	dir := t.TempDir()
+	file := filepath.Join(dir, ".env")
+	writeEnv(t, file, "VALUE=one\n")
+
+	w := newTestWatcher(t, "exit:1", file, "")
+	done := make(chan *os.ProcessState, 1)
+	go func() {
+		state, err := w.run(syscall.SIGTERM, 5*time.Second, 10*time.Millisecond)
+		if err != nil {
+			t.Errorf("run failed: %v", err)
+		}
+		done <- state
+	}()
+
+	// Give the command time to exit, leaving the watcher waiting for changes
+	time.Sleep(500 * time.Millisecond)
+	for _, sig := range []syscall.Signal{syscall.SIGWINCH, syscall.SIGUSR1, syscall.SIGHUP} {
+		if err := syscall.Kill(os.Getpid(), sig); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case <-done:
+		t.Fatal("run stopped on a signal other than INT or TERM")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case state := <-done:
+		if state != nil {
+			t.Errorf("Expected no state without a running command, got %v", state)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("run did not stop on SIGTERM")
+	}
+}
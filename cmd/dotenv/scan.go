@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/mew-sh/dotenv"
+)
+
+func cmdScan(args []string) int {
+	flags, files := newFlagSet("scan", "[-f FILES] [-allow FILE] [-entropy BITS] [-min-length N] [-json] [FILE...]")
+	allowFile := flags.String("allow", "", "allowlist of accepted findings (default "+dotenv.DefaultAllowlistFile+", if present)")
+	entropy := flags.Float64("entropy", dotenv.DefaultScanEntropy, "bits of entropy per character from which strings are reported")
+	minLength := flags.Int("min-length", dotenv.DefaultScanMinLength, "length from which strings are checked for entropy")
+	asJSON := flags.Bool("json", false, "print the findings as JSON")
+	flags.Parse(args)
+
+	opts := dotenv.ScanOptions{Entropy: *entropy, MinLength: *minLength}
+
+	allowPath := *allowFile
+	if allowPath == "" {
+		allowPath = dotenv.DefaultAllowlistFile
+	}
+	allow, err := dotenv.ReadAllowlist(allowPath)
+	switch {
+	case err == nil:
+		opts.Allow = allow
+	case *allowFile != "" || !errors.Is(err, fs.ErrNotExist):
+		fmt.Fprintf(os.Stderr, "Error reading allowlist: %v\n", err)
+		return 2
+	}
+
+	paths := targetFiles(*files)
+	if flags.NArg() > 0 {
+		paths = flags.Args()
+	}
+
+	findings := []dotenv.SecretFinding{}
+	for _, path := range paths {
+		found, err := dotenv.ScanFile(path, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		findings = append(findings, found...)
+	}
+
+	if *asJSON {
+		if err := writeJSON(os.Stdout, findings); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+		if len(findings) > 0 {
+			fmt.Fprintf(os.Stderr, "Add accepted keys or fingerprints (see -json) to %s\n", allowPath)
+		}
+	}
+
+	if len(findings) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"time"
+)
+
+// child is a command running under supervision of dotenv
+type child struct {
+	cmd *exec.Cmd
+	// ownGroup is set when the child leads its own process group, in which
+	// case signals and cleanup address the whole group
+	ownGroup bool
+	// done is closed once the child has exited; state is valid afterwards
+	done  chan struct{}
+	state *os.ProcessState
+}
+
+// startChild starts the command at path with the given argv and environment,
+// connected to dotenv's standard streams. When dotenv is not attached to a
+// terminal the child is placed in its own process group so that it and any
+// processes it spawns can be signalled and cleaned up together; on a
+// terminal it stays in the foreground group so job control keeps working.
+func startChild(path string, args, env []string) (*child, error) {
+	cmd := exec.Command(path, args[1:]...)
+	cmd.Args = args
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	c := &child{cmd: cmd, done: make(chan struct{})}
+	if !isTerminal(os.Stdin) {
+		c.ownGroup = setProcessGroup(cmd)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		cmd.Wait()
+		c.state = cmd.ProcessState
+		close(c.done)
+	}()
+
+	return c, nil
+}
+
+// signal delivers sig to the child, or to its process group
+func (c *child) signal(sig os.Signal) error {
+	return signalProcess(c.cmd.Process, sig, c.ownGroup)
+}
+
+// stop asks the child to exit with sig and kills it if it is still running
+// after grace. It returns once the child has exited.
+func (c *child) stop(sig os.Signal, grace time.Duration) {
+	if err := c.signal(sig); err != nil {
+		c.signal(os.Kill)
+	}
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-c.done:
+	case <-timer.C:
+		c.signal(os.Kill)
+		<-c.done
+	}
+	c.cleanup()
+}
+
+// cleanup terminates processes left behind in the child's process group
+// after the child itself has exited
+func (c *child) cleanup() {
+	if c.ownGroup {
+		signalProcess(c.cmd.Process, terminateSignal, true)
+	}
+}
+
+// supervise runs the command as a child process, forwarding signals received
+// by dotenv until it exits, and returns its final state
+func supervise(path string, args, env []string) (*os.ProcessState, error) {
+	signals := make(chan os.Signal, 8)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+
+	c, err := startChild(path, args, env)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		select {
+		case sig := <-signals:
+			c.forward(sig)
+		case <-c.done:
+			c.cleanup()
+			return c.state, nil
+		}
+	}
+}
+
+// forward passes a signal received by dotenv on to the child. Signals the
+// terminal sends to its whole foreground group already reached a child
+// sharing that group, so they are not delivered twice.
+func (c *child) forward(sig os.Signal) {
+	if !c.ownGroup && isTerminalSignal(sig) {
+		return
+	}
+	if err := c.signal(sig); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to forward %v: %v\n", sig, err)
+	}
+}
+
+// isTerminal reports whether f is a character device such as a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
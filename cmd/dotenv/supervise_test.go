@@ -0,0 +1,190 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestHelperProcess is not a real test: it is the child process started by
+// the supervision tests, behaving as GO_DOTENV_HELPER says. It appends a
+// line to GO_DOTENV_HELPER_FILE when it is ready.
+func TestHelperProcess(t *testing.T) {
+	mode := os.Getenv("GO_DOTENV_HELPER")
+	if mode == "" {
+		return
+	}
+	file := os.Getenv("GO_DOTENV_HELPER_FILE")
+
+	switch {
+	case strings.HasPrefix(mode, "exit:"):
+		code, _ := strconv.Atoi(strings.TrimPrefix(mode, "exit:"))
+		os.Exit(code)
+
+	case mode == "sleep":
+		// Reports the value of VALUE it was started with
+		appendLine(file, os.Getenv("VALUE"))
+		time.Sleep(time.Minute)
+
+	case mode == "grandchild":
+		// Starts a long-running grandchild and exits without waiting for it
+		path, argv, env := helperCommand("sleep", file+".grandchild")
+		cmd := exec.Command(path, argv[1:]...)
+		cmd.Env = env
+		if err := cmd.Start(); err != nil {
+			os.Exit(3)
+		}
+		appendLine(file, strconv.Itoa(cmd.Process.Pid))
+	}
+	os.Exit(0)
+}
+
+// helperCommand returns the path, argv and environment running the test
+// binary as a helper process in the given mode
+func helperCommand(mode, file string) (string, []string, []string) {
+	path, err := os.Executable()
+	if err != nil {
+		path = os.Args[0]
+	}
+	argv := []string{path, "-test.run=^TestHelperProcess$"}
+	env := append(os.Environ(), "GO_DOTENV_HELPER="+mode, "GO_DOTENV_HELPER_FILE="+file)
+	return path, argv, env
+}
+
+// appendLine appends line to file
+func appendLine(file, line string) {
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+// waitLines waits until file holds at least n lines and returns them
+func waitLines(t *testing.T, file string, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		data, _ := os.ReadFile(file)
+		if lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"); len(data) > 0 && len(lines) >= n {
+			return lines
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d lines in %s", n, file)
+	return nil
+}
+
+// processGone reports whether pid has exited; zombies count as gone
+func processGone(pid int) bool {
+	if errors.Is(syscall.Kill(pid, 0), syscall.ESRCH) {
+		return true
+	}
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) > 0 && fields[0] == "Z"
+}
+
+// withoutTerminal runs the test with stdin read from a pipe, so children
+// get their own process group as they do when dotenv is piped into
+func withoutTerminal(t *testing.T) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		r.Close()
+		w.Close()
+	})
+}
+
+func TestSuperviseExitCode(t *testing.T) {
+	for _, code := range []int{0, 1, 3, 42} {
+		path, argv, env := helperCommand(fmt.Sprintf("exit:%d", code), "")
+		state, err := supervise(path, argv, env)
+		if err != nil {
+			t.Fatalf("supervise failed: %v", err)
+		}
+		if got := exitCode(state); got != code {
+			t.Errorf("Expected exit code %d, got %d", code, got)
+		}
+	}
+}
+
+func TestSuperviseForwardsSIGTERM(t *testing.T) {
+	withoutTerminal(t)
+	file := filepath.Join(t.TempDir(), "ready")
+	path, argv, env := helperCommand("sleep", file)
+
+	type result struct {
+		state *os.ProcessState
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		state, err := supervise(path, argv, env)
+		done <- result{state, err}
+	}()
+
+	// The child is only started once supervise is handling signals
+	waitLines(t, file, 1)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatalf("supervise failed: %v", r.err)
+		}
+		if got := exitCode(r.state); got != 128+int(syscall.SIGTERM) {
+			t.Errorf("Expected exit code 143, got %d (%v)", got, r.state)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("child was not stopped by the forwarded SIGTERM")
+	}
+}
+
+func TestSuperviseCleansUpProcessGroup(t *testing.T) {
+	withoutTerminal(t)
+	file := filepath.Join(t.TempDir(), "pid")
+	path, argv, env := helperCommand("grandchild", file)
+
+	state, err := supervise(path, argv, env)
+	if err != nil {
+		t.Fatalf("supervise failed: %v", err)
+	}
+	if state.ExitCode() != 0 {
+		t.Fatalf("helper failed with %v", state)
+	}
+
+	pid, err := strconv.Atoi(waitLines(t, file, 1)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !processGone(pid) {
+		if time.Now().After(deadline) {
+			syscall.Kill(pid, syscall.SIGKILL)
+			t.Fatal("grandchild survived the child's exit")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
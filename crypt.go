@@ -0,0 +1,152 @@
+package dotenv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// EncryptedPrefix marks values encrypted with EncryptValue
+const EncryptedPrefix = "enc:v1:"
+
+// DefaultKeyFile is read by LoadKey when neither DOTENV_PRIVATE_KEY nor
+// DOTENV_KEY_FILE is set
+const DefaultKeyFile = ".env.key"
+
+// ErrNoKey is returned by LoadKey when no encryption key is configured
+var ErrNoKey = errors.New("no encryption key: set DOTENV_PRIVATE_KEY or create " + DefaultKeyFile)
+
+// GenerateKey returns a new random 256-bit key
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return key, nil
+}
+
+// LoadKey returns the hex-encoded key in DOTENV_PRIVATE_KEY, or else the key
+// stored in the file named by DOTENV_KEY_FILE (default .env.key). It returns
+// ErrNoKey when neither exists.
+func LoadKey() ([]byte, error) {
+	if hexKey := os.Getenv("DOTENV_PRIVATE_KEY"); hexKey != "" {
+		return decodeKey(hexKey)
+	}
+
+	filename := os.Getenv("DOTENV_KEY_FILE")
+	if filename == "" {
+		filename = DefaultKeyFile
+	}
+
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNoKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %s: %w", filename, err)
+	}
+
+	key, err := decodeKey(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return key, nil
+}
+
+// decodeKey parses a hex-encoded 256-bit key
+func decodeKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("invalid key: expected 64 hexadecimal characters")
+	}
+	return key, nil
+}
+
+// IsEncrypted reports whether value was produced by EncryptValue
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, EncryptedPrefix)
+}
+
+// EncryptValue encrypts the value of the variable name with AES-256-GCM and
+// returns it as enc:v1:BASE64. The name is authenticated along with the
+// value, so an encrypted value cannot be moved to another key.
+func EncryptValue(key []byte, name, value string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptValue reverses EncryptValue
+func DecryptValue(key []byte, name, value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, EncryptedPrefix)
+	if !ok {
+		return "", errors.New("value is not encrypted")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", errors.New("wrong key or corrupted value")
+	}
+	return string(plain), nil
+}
+
+// newAEAD creates the AES-256-GCM cipher for key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, errors.New("invalid key: expected 32 bytes")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// decrypt decrypts an encrypted value during parsing, loading the key the
+// first time one is needed. Without a key the value is left encrypted.
+func (p *Parser) decrypt(name, value string) (string, error) {
+	if !p.keyLoaded {
+		p.keyLoaded = true
+		key, err := LoadKey()
+		if err != nil && !errors.Is(err, ErrNoKey) {
+			return "", err
+		}
+		p.key = key
+	}
+
+	if p.key == nil {
+		return value, nil
+	}
+	return DecryptValue(p.key, name, value)
+}
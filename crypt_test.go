@@ -0,0 +1,103 @@
+package dotenv
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptValue(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	for _, value := range []string{"", "s3cr3t", "multi\nline \"quoted\" $VALUE", strings.Repeat("x", 4096)} {
+		encrypted, err := EncryptValue(key, "API_KEY", value)
+		if err != nil {
+			t.Fatalf("EncryptValue failed: %v", err)
+		}
+		if !IsEncrypted(encrypted) || needsQuoting(encrypted) {
+			t.Errorf("Unexpected encrypted form %q", encrypted)
+		}
+
+		decrypted, err := DecryptValue(key, "API_KEY", encrypted)
+		if err != nil || decrypted != value {
+			t.Errorf("Round trip of %q gave %q (%v)", value, decrypted, err)
+		}
+	}
+
+	a, _ := EncryptValue(key, "K", "same")
+	b, _ := EncryptValue(key, "K", "same")
+	if a == b {
+		t.Error("Expected a fresh nonce for every encryption")
+	}
+
+	other, _ := GenerateKey()
+	if _, err := DecryptValue(other, "K", a); err == nil {
+		t.Error("Expected error with the wrong key")
+	}
+	if _, err := DecryptValue(key, "OTHER", a); err == nil {
+		t.Error("Expected error when the value is moved to another key")
+	}
+	if _, err := DecryptValue(key, "K", a[:len(a)-4]+"AAAA"); err == nil {
+		t.Error("Expected error for a tampered value")
+	}
+	if _, err := DecryptValue(key, "K", EncryptedPrefix+"!!"); err == nil {
+		t.Error("Expected error for malformed value")
+	}
+}
+
+func TestParseDecrypts(t *testing.T) {
+	key, _ := GenerateKey()
+	encrypted, _ := EncryptValue(key, "PASSWORD", "hunter2")
+	input := "PASSWORD=" + encrypted + "\nDSN=\"user:${PASSWORD}@db\"\n"
+
+	t.Setenv("DOTENV_KEY_FILE", filepath.Join(t.TempDir(), "missing.key"))
+	t.Setenv("DOTENV_PRIVATE_KEY", "")
+
+	// Without a key values stay encrypted
+	env, err := Unmarshal(input)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if env["PASSWORD"] != encrypted {
+		t.Errorf("Expected encrypted value, got %q", env["PASSWORD"])
+	}
+
+	// With a key in the environment they are decrypted before expansion
+	t.Setenv("DOTENV_PRIVATE_KEY", hex.EncodeToString(key))
+	env, err = Unmarshal(input)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if env["PASSWORD"] != "hunter2" || env["DSN"] != "user:hunter2@db" {
+		t.Errorf("Unexpected values: %v", env)
+	}
+
+	// A key file works as well
+	keyFile := filepath.Join(t.TempDir(), "test.key")
+	if err := os.WriteFile(keyFile, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOTENV_PRIVATE_KEY", "")
+	t.Setenv("DOTENV_KEY_FILE", keyFile)
+	if env, err := Unmarshal(input); err != nil || env["PASSWORD"] != "hunter2" {
+		t.Errorf("Key file not used: %v (%v)", env, err)
+	}
+
+	// The wrong key is an error rather than silently returning ciphertext
+	other, _ := GenerateKey()
+	t.Setenv("DOTENV_PRIVATE_KEY", hex.EncodeToString(other))
+	if _, err := Unmarshal(input); err == nil || !strings.Contains(err.Error(), "cannot decrypt PASSWORD") {
+		t.Errorf("Expected decryption error, got %v", err)
+	}
+
+	t.Setenv("DOTENV_PRIVATE_KEY", "not-hex")
+	if _, err := LoadKey(); err == nil || errors.Is(err, ErrNoKey) {
+		t.Errorf("Expected invalid key error, got %v", err)
+	}
+}
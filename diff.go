@@ -0,0 +1,45 @@
+package dotenv
+
+import "sort"
+
+// DiffResult lists the keys that differ between two sets of variables.
+// Each slice is sorted.
+type DiffResult struct {
+	// Added holds keys present only in the second set
+	Added []string
+	// Removed holds keys present only in the first set
+	Removed []string
+	// Changed holds keys present in both sets with different values
+	Changed []string
+}
+
+// Empty reports whether the two sets were identical
+func (d DiffResult) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares two sets of variables, such as the results of two Read
+// calls, treating a as the old state and b as the new one.
+func Diff(a, b map[string]string) DiffResult {
+	var result DiffResult
+
+	for key, old := range a {
+		value, ok := b[key]
+		switch {
+		case !ok:
+			result.Removed = append(result.Removed, key)
+		case value != old:
+			result.Changed = append(result.Changed, key)
+		}
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			result.Added = append(result.Added, key)
+		}
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sort.Strings(result.Changed)
+	return result
+}
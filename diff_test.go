@@ -0,0 +1,43 @@
+package dotenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	staging := map[string]string{
+		"DATABASE_URL": "postgres://staging",
+		"DEBUG":        "true",
+		"PORT":         "8080",
+		"STAGING_ONLY": "1",
+	}
+	production := map[string]string{
+		"DATABASE_URL": "postgres://production",
+		"PORT":         "8080",
+		"SENTRY_DSN":   "https://sentry",
+		"CDN_URL":      "https://cdn",
+		"DEBUG":        "",
+	}
+
+	got := Diff(staging, production)
+	expected := DiffResult{
+		Added:   []string{"CDN_URL", "SENTRY_DSN"},
+		Removed: []string{"STAGING_ONLY"},
+		Changed: []string{"DATABASE_URL", "DEBUG"},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+	if got.Empty() {
+		t.Error("Expected differences")
+	}
+
+	if d := Diff(staging, staging); !d.Empty() {
+		t.Errorf("Expected no differences, got %+v", d)
+	}
+	if d := Diff(nil, map[string]string{"A": ""}); !reflect.DeepEqual(d.Added, []string{"A"}) {
+		t.Errorf("Expected A to be added, got %+v", d)
+	}
+}
@@ -0,0 +1,136 @@
+package dotenv
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// DocsFormat selects the output of Schema.Docs
+type DocsFormat string
+
+// Supported documentation formats
+const (
+	DocsMarkdown DocsFormat = "markdown"
+	DocsHTML     DocsFormat = "html"
+)
+
+// docsColumns are the headings of the generated table
+var docsColumns = []string{"Key", "Description", "Type", "Default", "Required", "Example"}
+
+// Docs renders a configuration reference for the schema as a Markdown or
+// HTML table, one row per key in schema order. Defaults of secret keys, see
+// Field.IsSecret, are not shown.
+func (s *Schema) Docs(format DocsFormat) (string, error) {
+	rows := make([][]string, len(s.Fields))
+	for i, f := range s.Fields {
+		def := f.Default
+		if f.IsSecret() && def != "" {
+			def = "(secret)"
+		}
+		required := "no"
+		if f.Required {
+			required = "yes"
+		}
+		rows[i] = []string{f.Key, f.Description, f.typeSummary(), def, required, f.Example}
+	}
+
+	switch format {
+	case DocsMarkdown:
+		return markdownTable(rows), nil
+	case DocsHTML:
+		return htmlTable(rows), nil
+	}
+	return "", fmt.Errorf("unknown docs format %q", format)
+}
+
+// typeSummary describes the field's type and constraints
+func (f *Field) typeSummary() string {
+	parts := []string{string(f.Type)}
+
+	bound := func(v *float64) string { return strconv.FormatFloat(*v, 'g', -1, 64) }
+	unit := ""
+	if f.Type == TypeString {
+		unit = " characters"
+	}
+	switch {
+	case f.Min != nil && f.Max != nil:
+		parts = append(parts, bound(f.Min)+" to "+bound(f.Max)+unit)
+	case f.Min != nil:
+		parts = append(parts, "at least "+bound(f.Min)+unit)
+	case f.Max != nil:
+		parts = append(parts, "at most "+bound(f.Max)+unit)
+	}
+	if len(f.Enum) > 0 {
+		parts = append(parts, "one of "+strings.Join(f.Enum, ", "))
+	}
+	if f.Pattern != "" {
+		parts = append(parts, "matching "+f.Pattern)
+	}
+	if f.Secret {
+		parts = append(parts, "secret")
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// markdownTable renders rows as a GitHub-flavoured Markdown table
+func markdownTable(rows [][]string) string {
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(docsColumns, " | ") + " |\n")
+	b.WriteString(strings.Repeat("| --- ", len(docsColumns)) + "|\n")
+
+	for _, row := range rows {
+		b.WriteString("|")
+		for i, cell := range row {
+			cell = strings.ReplaceAll(cell, "|", `\|`)
+			cell = strings.ReplaceAll(cell, "\n", " ")
+			// Keys, defaults and examples are literal text
+			if cell != "" && (i == 0 || i == 3 && cell != "(secret)" || i == 5) {
+				cell = markdownCode(cell)
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// markdownCode wraps s in a code span, using longer backtick fences when s
+// contains backticks
+func markdownCode(s string) string {
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
+// htmlTable renders rows as an HTML table
+func htmlTable(rows [][]string) string {
+	var b strings.Builder
+	b.WriteString("<table>\n  <thead>\n    <tr>")
+	for _, column := range docsColumns {
+		b.WriteString("<th>" + column + "</th>")
+	}
+	b.WriteString("</tr>\n  </thead>\n  <tbody>\n")
+
+	for _, row := range rows {
+		b.WriteString("    <tr>")
+		for i, cell := range row {
+			cell = html.EscapeString(cell)
+			if cell != "" && (i == 0 || i == 3 && cell != "(secret)" || i == 5) {
+				cell = "<code>" + cell + "</code>"
+			}
+			b.WriteString("<td>" + cell + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+
+	b.WriteString("  </tbody>\n</table>\n")
+	return b.String()
+}
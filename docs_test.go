@@ -0,0 +1,51 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchemaDocs(t *testing.T) {
+	input := "# Port the server | proxy listens on\n" +
+		"# @type int @required @min 1 @max 65535\n" +
+		"PORT=8080\n" +
+		"# @secret @example sk_test_123\n" +
+		"API_KEY=changeme\n" +
+		"# @enum debug,info\n" +
+		"LOG_LEVEL=<info>\n"
+
+	schema, err := ParseSchema(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+
+	markdown, err := schema.Docs(DocsMarkdown)
+	if err != nil {
+		t.Fatalf("Docs failed: %v", err)
+	}
+	expected := "| Key | Description | Type | Default | Required | Example |\n" +
+		"| --- | --- | --- | --- | --- | --- |\n" +
+		"| `PORT` | Port the server \\| proxy listens on | int, 1 to 65535 | `8080` | yes |  |\n" +
+		"| `API_KEY` |  | string, secret | (secret) | no | `sk_test_123` |\n" +
+		"| `LOG_LEVEL` |  | string, one of debug, info | `<info>` | no |  |\n"
+	if markdown != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, markdown)
+	}
+
+	page, err := schema.Docs(DocsHTML)
+	if err != nil {
+		t.Fatalf("Docs failed: %v", err)
+	}
+	for _, want := range []string{"<th>Key</th>", "<td><code>PORT</code></td>", "<code>&lt;info&gt;</code>", "<td>(secret)</td>"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Expected HTML to contain %q:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, "changeme") || strings.Contains(markdown, "changeme") {
+		t.Error("Secret default leaked into docs")
+	}
+
+	if _, err := schema.Docs("pdf"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
@@ -0,0 +1,297 @@
+package dotenv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Document is a comment-preserving representation of a .env file. Unlike
+// Parse, which only returns the resulting values, a Document remembers every
+// line verbatim so that edits made through Set and Unset leave comments,
+// blank lines, ordering and formatting of untouched entries intact.
+//
+// Values in a Document are decoded (quotes and escapes removed) but not
+// expanded; $VAR references are kept as written.
+//
+// The zero value is an empty document ready to use.
+type Document struct {
+	nodes []*node
+	// noFinalNewline records that the source did not end with a newline
+	noFinalNewline bool
+}
+
+// node is a single logical line of a Document: a blank line, a comment or an
+// assignment, which may span several physical lines when its value does
+type node struct {
+	// raw is the exact source text without the terminating newline
+	raw string
+	// line is the line number the node starts on in the source
+	line int
+	// entry is nil for blank lines and comments
+	entry *docEntry
+}
+
+// docEntry describes the assignment held by a node
+type docEntry struct {
+	key       string
+	value     string
+	export    bool
+	separator byte
+	quote     byte
+	// valueStart and valueEnd delimit the raw value, including quotes,
+	// within node.raw
+	valueStart, valueEnd int
+}
+
+// ParseDocument parses .env content into a comment-preserving Document
+func ParseDocument(reader io.Reader) (*Document, error) {
+	src, err := readAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading input: %w", err)
+	}
+
+	return parseDocument(src)
+}
+
+// ReadDocument reads a .env file into a comment-preserving Document
+func ReadDocument(filename string) (*Document, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return doc, nil
+}
+
+// parseDocument splits src into nodes, using the lexer for assignments and
+// keeping everything between them as blank or comment lines
+func parseDocument(src []byte) (*Document, error) {
+	doc := &Document{}
+	parser := NewParserWithOptions(false)
+	parser.env = make(map[string]string)
+
+	lex := newLexer(parser, src)
+	cursor, line := 0, 1
+
+	for {
+		stmt, ok, err := lex.next()
+		if err != nil {
+			return nil, fmt.Errorf("parse error on line %d: %w", stmt.line, err)
+		}
+		if !ok {
+			break
+		}
+
+		lineStart := bytes.LastIndexByte(src[:stmt.start], '\n') + 1
+		line = doc.appendTrivia(src[cursor:lineStart], line)
+
+		raw := string(src[lineStart:stmt.end])
+		doc.nodes = append(doc.nodes, &node{
+			raw:  raw,
+			line: line,
+			entry: &docEntry{
+				key:        stmt.key,
+				value:      stmt.value,
+				export:     stmt.export,
+				separator:  stmt.separator,
+				quote:      stmt.quote,
+				valueStart: stmt.valueStart - lineStart,
+				valueEnd:   stmt.valueEnd - lineStart,
+			},
+		})
+		line += strings.Count(raw, "\n") + 1
+
+		cursor = min(stmt.end+1, len(src))
+	}
+
+	doc.noFinalNewline = len(src) > 0 && src[len(src)-1] != '\n'
+
+	rest := src[cursor:]
+	if len(rest) > 0 && rest[len(rest)-1] != '\n' {
+		rest = append(rest[:len(rest):len(rest)], '\n')
+	}
+	doc.appendTrivia(rest, line)
+
+	return doc, nil
+}
+
+// appendTrivia adds the blank and comment lines in text, which is either
+// empty or ends with a newline, and returns the next line number
+func (d *Document) appendTrivia(text []byte, line int) int {
+	for len(text) > 0 {
+		i := bytes.IndexByte(text, '\n')
+		d.nodes = append(d.nodes, &node{raw: string(text[:i]), line: line})
+		text = text[i+1:]
+		line++
+	}
+	return line
+}
+
+// Keys returns the keys assigned in the document in order of first
+// appearance, without duplicates
+func (d *Document) Keys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+
+	for _, n := range d.nodes {
+		if n.entry != nil && !seen[n.entry.key] {
+			seen[n.entry.key] = true
+			keys = append(keys, n.entry.key)
+		}
+	}
+
+	return keys
+}
+
+// Get returns the unexpanded value of key. When a key is assigned more than
+// once, the last assignment wins, as it does for Parse.
+func (d *Document) Get(key string) (string, bool) {
+	if n := d.last(key); n != nil {
+		return n.entry.value, true
+	}
+	return "", false
+}
+
+// Set assigns value to key. An existing assignment is rewritten in place,
+// keeping its export prefix and any inline comment; otherwise a new line is
+// appended to the end of the document.
+func (d *Document) Set(key, value string) {
+	n := d.last(key)
+	if n == nil {
+		d.nodes = append(d.nodes, newEntryNode(key, value))
+		d.noFinalNewline = false
+		return
+	}
+
+	d.setValue(n, value)
+}
+
+// Unset removes every assignment of key and reports whether any existed
+func (d *Document) Unset(key string) bool {
+	kept := d.nodes[:0]
+	removed := false
+
+	for _, n := range d.nodes {
+		if n.entry != nil && n.entry.key == key {
+			removed = true
+			continue
+		}
+		kept = append(kept, n)
+	}
+
+	d.nodes = kept
+	return removed
+}
+
+// Values returns the unexpanded values of all keys in the document
+func (d *Document) Values() map[string]string {
+	result := make(map[string]string)
+	for _, n := range d.nodes {
+		if n.entry != nil {
+			result[n.entry.key] = n.entry.value
+		}
+	}
+	return result
+}
+
+// String renders the document as .env file content
+func (d *Document) String() string {
+	var b strings.Builder
+	for i, n := range d.nodes {
+		b.WriteString(n.raw)
+		if i < len(d.nodes)-1 || !d.noFinalNewline {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// Save writes the document to filename atomically, preserving the
+// permissions of an existing file
+func (d *Document) Save(filename string) error {
+	return writeFileAtomic(filename, []byte(d.String()))
+}
+
+// remove deletes a node from the document
+func (d *Document) remove(target *node) {
+	for i, n := range d.nodes {
+		if n == target {
+			d.nodes = append(d.nodes[:i], d.nodes[i+1:]...)
+			return
+		}
+	}
+}
+
+// snapshot records the document's nodes and returns a function restoring
+// them, keeping node identity so references held elsewhere stay valid
+func (d *Document) snapshot() func() {
+	nodes := append([]*node(nil), d.nodes...)
+	saved := make([]node, len(nodes))
+	entries := make([]docEntry, len(nodes))
+	for i, n := range nodes {
+		saved[i] = *n
+		if n.entry != nil {
+			entries[i] = *n.entry
+		}
+	}
+	noFinalNewline := d.noFinalNewline
+
+	return func() {
+		for i, n := range nodes {
+			*n = saved[i]
+			if n.entry != nil {
+				*n.entry = entries[i]
+			}
+		}
+		d.nodes = nodes
+		d.noFinalNewline = noFinalNewline
+	}
+}
+
+// last returns the node holding the effective assignment of key
+func (d *Document) last(key string) *node {
+	for i := len(d.nodes) - 1; i >= 0; i-- {
+		if n := d.nodes[i]; n.entry != nil && n.entry.key == key {
+			return n
+		}
+	}
+	return nil
+}
+
+// setValue replaces the raw value portion of an assignment node
+func (d *Document) setValue(n *node, value string) {
+	formatted := formatValue(value)
+	e := n.entry
+
+	n.raw = n.raw[:e.valueStart] + formatted + n.raw[e.valueEnd:]
+	e.value = value
+	e.valueEnd = e.valueStart + len(formatted)
+	e.quote = 0
+	if formatted != value {
+		e.quote = formatted[0]
+	}
+}
+
+// newEntryNode creates a node holding a plain KEY=value assignment
+func newEntryNode(key, value string) *node {
+	formatted := formatValue(value)
+	e := &docEntry{
+		key:        key,
+		value:      value,
+		separator:  '=',
+		valueStart: len(key) + 1,
+		valueEnd:   len(key) + 1 + len(formatted),
+	}
+	if formatted != value {
+		e.quote = formatted[0]
+	}
+
+	return &node{raw: key + "=" + formatted, entry: e}
+}
@@ -0,0 +1,122 @@
+package dotenv
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDocumentRoundTrip(t *testing.T) {
+	content := `# Database settings
+DB_HOST=localhost   # inline comment
+
+export DB_PORT: 5432
+DB_URL="postgres://${DB_HOST}:${DB_PORT}"
+MULTI="line1
+line2"
+  INDENTED='literal'
+# trailing comment`
+
+	doc, err := ParseDocument(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+
+	if doc.String() != content {
+		t.Errorf("Document did not round trip:\n%s", doc.String())
+	}
+
+	expectedKeys := []string{"DB_HOST", "DB_PORT", "DB_URL", "MULTI", "INDENTED"}
+	if keys := doc.Keys(); strings.Join(keys, ",") != strings.Join(expectedKeys, ",") {
+		t.Errorf("Expected keys %v, got %v", expectedKeys, keys)
+	}
+
+	if value, ok := doc.Get("DB_URL"); !ok || value != "postgres://${DB_HOST}:${DB_PORT}" {
+		t.Errorf("Expected unexpanded DB_URL, got %q", value)
+	}
+}
+
+func TestDocumentEdit(t *testing.T) {
+	content := `# Header
+A=1 # keep me
+export B=two
+C=
+C=dup # last wins
+`
+
+	doc, err := ParseDocument(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+
+	doc.Set("A", "new value")
+	doc.Set("B", "2")
+	doc.Set("C", "3")
+	doc.Set("D", `it's "quoted"`)
+	if !doc.Unset("C") {
+		t.Error("Unset should report removing C")
+	}
+	if doc.Unset("MISSING") {
+		t.Error("Unset should report missing key")
+	}
+
+	expected := "# Header\nA=\"new value\" # keep me\nexport B=2\nD=`it's \"quoted\"`\n"
+	if doc.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, doc.String())
+	}
+
+	// The edited content parses back to the same values
+	env, err := Unmarshal(doc.String())
+	if err != nil {
+		t.Fatalf("Failed to parse edited document: %v", err)
+	}
+	if env["A"] != "new value" || env["B"] != "2" || env["D"] != `it's "quoted"` {
+		t.Errorf("Unexpected values after edit: %v", env)
+	}
+
+	// Empty value followed by a comment
+	doc, _ = ParseDocument(strings.NewReader("E= # note"))
+	doc.Set("E", "x")
+	if doc.String() != "E=x # note" {
+		t.Errorf("Unexpected edit of empty value: %q", doc.String())
+	}
+}
+
+func TestDocumentSave(t *testing.T) {
+	filename := createTempEnvFile(t, "# keep\nKEY=value\n")
+	if err := os.Chmod(filename, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := ReadDocument(filename)
+	if err != nil {
+		t.Fatalf("ReadDocument failed: %v", err)
+	}
+
+	var empty Document
+	empty.Set("ONLY", "1")
+	if empty.String() != "ONLY=1\n" {
+		t.Errorf("Unexpected zero document output: %q", empty.String())
+	}
+
+	doc.Set("OTHER", "x")
+	if err := doc.Save(filename); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "# keep\nKEY=value\nOTHER=x\n" {
+		t.Errorf("Unexpected saved content: %q", data)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Save should preserve permissions, got %v", info.Mode().Perm())
+	}
+}
@@ -19,12 +19,14 @@
 package dotenv
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 // Default .env filename
@@ -34,18 +36,32 @@ const DefaultEnvFile = ".env"
 // If no files are specified, it defaults to loading ".env" from the current directory.
 // Existing environment variables take precedence and will not be overwritten.
 func Load(filenames ...string) error {
-	return load(false, filenames...)
+	return LoadWithOptions(Options{}, filenames...)
 }
 
 // Overload reads the specified .env files and loads the environment variables.
 // Unlike Load, this will overwrite existing environment variables.
 func Overload(filenames ...string) error {
-	return load(true, filenames...)
+	return LoadWithOptions(Options{Override: true}, filenames...)
 }
 
 // Read reads the specified .env files and returns a map of key-value pairs
-// without modifying the actual environment variables.
+// without modifying the actual environment variables. Files ending in .json,
+// .properties or .ini are read with ParseJSON (default options),
+// ParseProperties or ParseINI respectively.
 func Read(filenames ...string) (map[string]string, error) {
+	return read(NewParser, filenames...)
+}
+
+// ReadStrict behaves like Read but parses each file with a strict parser,
+// failing on duplicate keys, colon separators, unmatched quotes and unquoted
+// values containing whitespace. See NewStrictParser.
+func ReadStrict(filenames ...string) (map[string]string, error) {
+	return ReadWithOptions(Options{Strict: true}, filenames...)
+}
+
+// read is the internal implementation for Read and ReadStrict
+func read(newParser func() *Parser, filenames ...string) (map[string]string, error) {
 	if len(filenames) == 0 {
 		filenames = []string{DefaultEnvFile}
 	}
@@ -53,7 +69,7 @@ func Read(filenames ...string) (map[string]string, error) {
 	result := make(map[string]string)
 
 	for _, filename := range filenames {
-		env, err := readFile(filename)
+		env, err := readFile(filename, newParser())
 		if err != nil {
 			return nil, err
 		}
@@ -108,14 +124,7 @@ func Write(env map[string]string, filename string) error {
 		return err
 	}
 
-	// Ensure directory exists
-	dir := filepath.Dir(filename)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", dir, err)
-	}
-
-	// Write file with appropriate permissions
-	return os.WriteFile(filename, []byte(content+"\n"), 0644)
+	return writeFileAtomic(filename, []byte(content+"\n"))
 }
 
 // Must is a helper that wraps Load and panics if an error occurs.
@@ -127,45 +136,116 @@ func Must(filenames ...string) {
 	}
 }
 
-// load is the internal implementation for Load and Overload
-func load(overload bool, filenames ...string) error {
-	env, err := Read(filenames...)
+// readFile reads a single .env file and returns the parsed environment variables.
+// Files with a .json, .properties or .ini extension are read with ParseJSON,
+// ParseProperties or ParseINI instead.
+func readFile(filename string, parser *Parser) (map[string]string, error) {
+	data, err := os.ReadFile(filename)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
 	}
 
-	for key, value := range env {
-		if overload || os.Getenv(key) == "" {
-			if err := os.Setenv(key, value); err != nil {
-				return fmt.Errorf("failed to set environment variable %s: %w", key, err)
-			}
-		}
+	var env map[string]string
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		env, err = ParseJSON(bytes.NewReader(data), JSONOptions{})
+	case ".properties":
+		env, err = ParseProperties(bytes.NewReader(data))
+	case ".ini":
+		env, err = ParseINI(bytes.NewReader(data))
+	default:
+		env, err = parser.parseBytes(data)
 	}
-
-	return nil
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return env, nil
 }
 
-// readFile reads a single .env file and returns the parsed environment variables
-func readFile(filename string) (map[string]string, error) {
-	file, err := os.Open(filename)
+// writeFileAtomic writes data to a temporary file next to filename and
+// renames it into place, so readers never observe a partially written file.
+// An existing file keeps its permissions; new files are created with 0644.
+// Symlinks are followed, so the file they point to is replaced.
+func writeFileAtomic(filename string, data []byte) error {
+	// Write through a symlinked .env instead of replacing the link
+	if target, err := filepath.EvalSymlinks(filename); err == nil {
+		filename = target
+	}
+
+	// Ensure directory exists
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(filename); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filename)+".tmp*")
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+		return fmt.Errorf("failed to create temporary file for %s: %w", filename, err)
 	}
-	defer file.Close()
+	defer os.Remove(tmp.Name()) // No-op once renamed
 
-	return Parse(file)
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", filename, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", filename, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filename, err)
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return fmt.Errorf("failed to set permissions on %s: %w", filename, err)
+	}
+
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", filename, err)
+	}
+	return nil
 }
 
 // formatEnvLine formats a key-value pair for .env file output
 func formatEnvLine(key, value string) string {
+	return key + "=" + formatValue(value)
+}
+
+// formatValue formats a value for .env file output, quoting it only when needed
+func formatValue(value string) string {
 	// Simple values that don't need quoting
 	if !needsQuoting(value) {
-		return fmt.Sprintf("%s=%s", key, value)
+		return value
+	}
+
+	// Values containing both quote characters read better backtick-quoted,
+	// which is literal and needs no escaping
+	if canBacktickQuote(value) {
+		return "`" + value + "`"
 	}
 
 	// Quote and escape the value
-	escaped := escapeValue(value)
-	return fmt.Sprintf(`%s="%s"`, key, escaped)
+	return `"` + escapeValue(value) + `"`
+}
+
+// canBacktickQuote reports whether value contains both ' and " and can be
+// written verbatim between backticks
+func canBacktickQuote(value string) bool {
+	if !strings.ContainsRune(value, '\'') || !strings.ContainsRune(value, '"') {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c == '`' || c < 0x20 || c == 0x7f {
+			return false
+		}
+	}
+
+	return utf8.ValidString(value)
 }
 
 // needsQuoting determines if a value needs to be quoted
@@ -174,22 +254,64 @@ func needsQuoting(value string) bool {
 		return true
 	}
 
-	for _, char := range value {
-		switch char {
-		case ' ', '\t', '\n', '\r', '"', '\'', '\\', '#', '$':
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case ' ', '\t', '\n', '\r', '"', '\'', '`', '\\', '#', '$':
 			return true
+		default:
+			if c < 0x20 || c == 0x7f {
+				return true
+			}
 		}
 	}
 
-	return false
+	return !utf8.ValidString(value)
 }
 
-// escapeValue escapes special characters in a value for double-quoted output
+// escapeValue escapes a value for double-quoted output. It is the inverse of
+// the parser's escape handling, so any byte sequence survives a round trip:
+// control characters and invalid UTF-8 are written as \xHH.
 func escapeValue(value string) string {
-	value = strings.ReplaceAll(value, `\`, `\\`)
-	value = strings.ReplaceAll(value, `"`, `\"`)
-	value = strings.ReplaceAll(value, "\n", `\n`)
-	value = strings.ReplaceAll(value, "\r", `\r`)
-	value = strings.ReplaceAll(value, "\t", `\t`)
-	return value
+	var b strings.Builder
+	b.Grow(len(value))
+
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRuneInString(value[i:])
+		if r == utf8.RuneError && size <= 1 {
+			fmt.Fprintf(&b, `\x%02x`, value[i])
+			i++
+			continue
+		}
+		i += size
+
+		switch r {
+		case '\\', '"', '$':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case 0:
+			b.WriteString(`\0`)
+		case '\a':
+			b.WriteString(`\a`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\v':
+			b.WriteString(`\v`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\x%02x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+
+	return b.String()
 }
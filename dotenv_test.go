@@ -2,6 +2,7 @@ package dotenv
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -236,6 +237,120 @@ SINGLE='no\nexpansion'
 	}
 }
 
+func TestExtendedEscapeSequences(t *testing.T) {
+	content := `UNICODE="caf\u00e9"
+WIDE="\U0001F600"
+HEX="\x41\xff"
+CONTROL="\0\a\b\f\v"
+DOLLAR="\$HOME \${HOME}"
+CONTINUATION="first \
+second"
+MALFORMED="\u12 \xZZ \q"
+`
+
+	env, err := Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"UNICODE":      "café",
+		"WIDE":         "\U0001F600",
+		"HEX":          "A\xff",
+		"CONTROL":      "\x00\a\b\f\v",
+		"DOLLAR":       "$HOME ${HOME}",
+		"CONTINUATION": "first second",
+		"MALFORMED":    `\u12 \xZZ \q`,
+	}
+
+	for key, expected := range tests {
+		if actual := env[key]; actual != expected {
+			t.Errorf("Expected %s=%q, got %q", key, expected, actual)
+		}
+	}
+}
+
+func TestMarshalRoundTripBytes(t *testing.T) {
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+
+	env := map[string]string{
+		"ALL_BYTES": string(all),
+		"INVALID":   "\xc3\x28 broken utf8",
+		"DOLLAR":    "$HOME and ${PATH}",
+		"UNICODE":   "日本語 \u2028 ✓",
+		"TRAILING":  `ends with backslash\`,
+	}
+
+	result, err := Marshal(env)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	parsed, err := Unmarshal(result)
+	if err != nil {
+		t.Fatalf("Failed to parse marshaled content: %v", err)
+	}
+
+	for key, expected := range env {
+		if actual := parsed[key]; actual != expected {
+			t.Errorf("Round trip mismatch for %s: expected %q, got %q", key, expected, actual)
+		}
+	}
+
+	// A second round trip produces identical output
+	again, err := Marshal(parsed)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if again != result {
+		t.Errorf("Marshal output is not stable:\n%s\n%s", result, again)
+	}
+}
+
+func TestBacktickQuotes(t *testing.T) {
+	content := "BOTH=`He said \"it's\"`\n" +
+		"LITERAL=`$HOME \\n # not a comment`\n" +
+		"MULTI=`a\nb` # comment\n"
+
+	env, err := Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"BOTH":    `He said "it's"`,
+		"LITERAL": `$HOME \n # not a comment`,
+		"MULTI":   "a\nb",
+	}
+
+	for key, expected := range tests {
+		if actual := env[key]; actual != expected {
+			t.Errorf("Expected %s=%q, got %q", key, expected, actual)
+		}
+	}
+
+	if _, err := Parse(strings.NewReader("KEY=`unterminated")); err == nil {
+		t.Error("Expected error for unterminated backtick quote")
+	}
+
+	marshaled, err := Marshal(map[string]string{
+		"BOTH":     `it's "quoted"`,
+		"BACKTICK": "`cmd`",
+		"ONLY":     `"double"`,
+	})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	expected := "BACKTICK=\"`cmd`\"\nBOTH=`it's \"quoted\"`\nONLY=\"\\\"double\\\"\""
+	if marshaled != expected {
+		t.Errorf("Expected marshaled output:\n%s\ngot:\n%s", expected, marshaled)
+	}
+}
+
 func TestInlineComments(t *testing.T) {
 	content := `KEY1=value1 # This is a comment
 KEY2="quoted value" # Comment after quotes
@@ -333,6 +448,143 @@ func TestErrorCases(t *testing.T) {
 	}
 }
 
+func TestStrictMode(t *testing.T) {
+	valid := `KEY1=value1
+KEY2="value with spaces"
+KEY3='single quoted'
+export KEY4="exported" # comment
+EMPTY=
+EMPTY_COMMENT= # comment
+ESCAPED=a\"b
+`
+	if _, err := NewStrictParser().Parse(strings.NewReader(valid)); err != nil {
+		t.Fatalf("Strict parse of valid content failed: %v", err)
+	}
+	if _, err := NewStrictParser().Parse(strings.NewReader("KEY=value\r\nOTHER=1\r\n")); err != nil {
+		t.Errorf("Strict parse of CRLF content failed: %v", err)
+	}
+
+	invalid := map[string]string{
+		"duplicate key":     "KEY=one\nKEY=two",
+		"colon separator":   "KEY: value",
+		"unmatched double":  `KEY="value`,
+		"unmatched single":  `KEY=value'`,
+		"lone quote":        `KEY="`,
+		"unquoted spaces":   "KEY=value with spaces",
+		"unquoted tab":      "KEY=value\twith\ttab",
+		"mismatched quotes": `KEY="value'`,
+		"trailing spaces":   "KEY=value   ",
+		"trailing tab":      "KEY=value\t",
+		"trailing space lf": "KEY=value \nOTHER=1",
+		"inline comment":    "KEY=value # c",
+		"inner quote":       `KEY=a"b`,
+		"leading quote":     `KEY=a'b'`,
+		"backtick":          "KEY=a`b",
+	}
+
+	for name, content := range invalid {
+		if _, err := NewStrictParser().Parse(strings.NewReader(content)); err == nil {
+			t.Errorf("%s: expected strict parse error for %q", name, content)
+		}
+	}
+
+	// The default parser stays lenient
+	if _, err := Parse(strings.NewReader("KEY=one\nKEY: two")); err != nil {
+		t.Errorf("Default parser should accept duplicates and colons: %v", err)
+	}
+
+	tmpFile := createTempEnvFile(t, "KEY=one\nKEY=two\n")
+	if _, err := ReadStrict(tmpFile); err == nil || !strings.Contains(err.Error(), "duplicate key") {
+		t.Errorf("Expected duplicate key error from ReadStrict, got %v", err)
+	}
+}
+
+func TestQuoteErrors(t *testing.T) {
+	valid := map[string]string{
+		`KEY="escaped \" quote"`:     `escaped " quote`,
+		`KEY="trailing backslash\\"`: `trailing backslash\`,
+		`KEY='literal \'`:            `literal \`,
+		`KEY="value" # comment`:      "value",
+		`KEY="has # hash" # comment`: "has # hash",
+		`KEY='single' `:              "single",
+	}
+
+	for content, expected := range valid {
+		env, err := Parse(strings.NewReader(content))
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", content, err)
+			continue
+		}
+		if env["KEY"] != expected {
+			t.Errorf("Parse(%q): expected %q, got %q", content, expected, env["KEY"])
+		}
+	}
+
+	invalid := map[string]string{
+		`KEY="abc`:        "unterminated",
+		`KEY='abc`:        "unterminated",
+		`KEY="abc\"`:      "unterminated",
+		`KEY="abc" extra`: "after closing",
+		`KEY='abc'extra`:  "after closing",
+		`KEY="a" "b"`:     "after closing",
+	}
+
+	for content, message := range invalid {
+		_, err := Parse(strings.NewReader(content))
+		if err == nil {
+			t.Errorf("Expected error for %q", content)
+		} else if !strings.Contains(err.Error(), message) {
+			t.Errorf("Error for %q should mention %q, got %v", content, message, err)
+		}
+	}
+}
+
+func TestLexer(t *testing.T) {
+	long := strings.Repeat("x", 200*1024)
+	content := "LONG=" + long + `
+MULTILINE="line1
+line2"
+SINGLE_MULTI='a
+b'
+LITERAL='$HOME stays'
+export=not_a_prefix
+export   SPACED = value
+DOLLAR=cost $5 and $
+UNCLOSED=${NOT_CLOSED
+CRLF=value` + "\r\n" + `AFTER=ok
+`
+
+	env, err := Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"LONG":         long,
+		"MULTILINE":    "line1\nline2",
+		"SINGLE_MULTI": "a\nb",
+		"LITERAL":      "$HOME stays",
+		"export":       "not_a_prefix",
+		"SPACED":       "value",
+		"DOLLAR":       "cost $5 and $",
+		"UNCLOSED":     "${NOT_CLOSED",
+		"CRLF":         "value",
+		"AFTER":        "ok",
+	}
+
+	for key, expected := range tests {
+		if actual := env[key]; actual != expected {
+			t.Errorf("Expected %s=%.40q, got %.40q", key, expected, actual)
+		}
+	}
+
+	// Line numbers account for multi-line values
+	_, err = Parse(strings.NewReader("A=\"x\ny\"\nB=ok\nbad line\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("Expected error on line 4, got %v", err)
+	}
+}
+
 // Helper function to create temporary .env file
 func createTempEnvFile(t *testing.T, content string) string {
 	tmpFile := t.TempDir() + "/.env"
@@ -342,3 +594,34 @@ func createTempEnvFile(t *testing.T, content string) string {
 	}
 	return tmpFile
 }
+
+func TestWriteSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "shared.env")
+	if err := os.WriteFile(target, []byte("OLD=1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, ".env")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if err := Write(map[string]string{"NEW": "2"}, link); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Error("Write replaced the symlink with a regular file")
+	}
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "NEW=2\n" {
+		t.Errorf("Expected the link target to be rewritten, got %q", data)
+	}
+}
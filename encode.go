@@ -0,0 +1,247 @@
+package dotenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format identifies a configuration format that environment maps can be
+// converted to
+type Format string
+
+// Supported formats
+const (
+	// FormatDotenv is the .env syntax produced by Marshal
+	FormatDotenv Format = "dotenv"
+	// FormatJSON is a flat JSON object of strings
+	FormatJSON Format = "json"
+	// FormatYAML is a flat YAML mapping of double-quoted keys and strings
+	FormatYAML Format = "yaml"
+	// FormatTOML is a flat TOML table of basic strings
+	FormatTOML Format = "toml"
+	// FormatDocker is the env-file format of docker run --env-file, which
+	// takes values verbatim and has no quoting
+	FormatDocker Format = "docker"
+	// FormatShell is a POSIX shell script of export statements
+	FormatShell Format = "shell"
+	// FormatSystemd is the EnvironmentFile= format of systemd units
+	FormatSystemd Format = "systemd"
+	// FormatProperties is the Java .properties format
+	FormatProperties Format = "properties"
+	// FormatINI is the INI format; all keys are written outside of sections
+	FormatINI Format = "ini"
+)
+
+// ParseFormat maps a format name, including aliases such as yml, env and
+// sh, to a Format
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "dotenv", "env", ".env":
+		return FormatDotenv, nil
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "toml":
+		return FormatTOML, nil
+	case "docker", "env-file", "envfile":
+		return FormatDocker, nil
+	case "shell", "sh", "bash":
+		return FormatShell, nil
+	case "systemd":
+		return FormatSystemd, nil
+	case "properties", "java":
+		return FormatProperties, nil
+	case "ini":
+		return FormatINI, nil
+	}
+	return "", fmt.Errorf("unsupported format %q", name)
+}
+
+// MarshalFormat serializes env in the given format. Keys are sorted for
+// consistent output, and non-empty output ends with a newline.
+func MarshalFormat(env map[string]string, format Format) (string, error) {
+	switch format {
+	case FormatDotenv:
+		content, err := Marshal(env)
+		if err != nil || content == "" {
+			return content, err
+		}
+		return content + "\n", nil
+	case FormatJSON:
+		return marshalJSON(env)
+	case FormatShell:
+		return Export(env, ShellBash)
+	case FormatYAML:
+		return marshalLines(env, func(key, value string) (string, error) {
+			// Keys are quoted too: YAML 1.1 reads names such as ON, NO, Y
+			// and NULL as booleans or null
+			return quoteYAML(key) + ": " + quoteYAML(value), nil
+		})
+	case FormatTOML:
+		return marshalLines(env, func(key, value string) (string, error) {
+			if !utf8.ValidString(value) {
+				return "", fmt.Errorf("cannot encode %s as TOML: value is not valid UTF-8", key)
+			}
+			return key + " = " + quoteTOML(value), nil
+		})
+	case FormatDocker:
+		return marshalLines(env, func(key, value string) (string, error) {
+			if strings.ContainsAny(value, "\r\n") {
+				return "", fmt.Errorf("cannot encode %s as a Docker env-file: value contains a line break", key)
+			}
+			return key + "=" + value, nil
+		})
+	case FormatSystemd:
+		return marshalLines(env, func(key, value string) (string, error) {
+			return key + "=" + quoteSystemd(value), nil
+		})
+	case FormatProperties:
+		return marshalLines(env, func(key, value string) (string, error) {
+			return escapeProperty(key, true) + "=" + escapeProperty(value, false), nil
+		})
+	case FormatINI:
+		return marshalLines(env, func(key, value string) (string, error) {
+			quoted, err := quoteINI(value)
+			if err != nil {
+				return "", fmt.Errorf("cannot encode %s as INI: %w", key, err)
+			}
+			return key + " = " + quoted, nil
+		})
+	}
+	return "", fmt.Errorf("unsupported format %q", format)
+}
+
+// marshalLines renders one line per key in sorted order
+func marshalLines(env map[string]string, line func(key, value string) (string, error)) (string, error) {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		l, err := line(key, env[key])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(l)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// marshalJSON renders env as an indented JSON object
+func marshalJSON(env map[string]string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if env == nil {
+		env = map[string]string{}
+	}
+	if err := enc.Encode(env); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// quoteYAML double-quotes value using YAML escape sequences, so values are
+// always read back as strings (no true/1.0/null surprises)
+func quoteYAML(value string) string {
+	return quoteEscaped(value, func(b *strings.Builder, r rune) bool {
+		switch r {
+		case 0:
+			b.WriteString(`\0`)
+		case '\a':
+			b.WriteString(`\a`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\v':
+			b.WriteString(`\v`)
+		case 0x1b:
+			b.WriteString(`\e`)
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+// quoteTOML double-quotes value as a TOML basic string
+func quoteTOML(value string) string {
+	return quoteEscaped(value, func(b *strings.Builder, r rune) bool {
+		switch r {
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			return false
+		}
+		return true
+	})
+}
+
+// quoteEscaped double-quotes value, escaping quotes, backslashes, \n, \r
+// and \t. Other control characters are offered to extra and otherwise
+// written as \uXXXX; invalid UTF-8 bytes become U+FFFD.
+func quoteEscaped(value string, extra func(b *strings.Builder, r rune) bool) string {
+	var b strings.Builder
+	b.Grow(len(value) + 2)
+	b.WriteByte('"')
+
+	for _, r := range value {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r >= 0x20 && r != 0x7f {
+				b.WriteRune(r)
+			} else if !extra(&b, r) {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			}
+		}
+	}
+
+	b.WriteByte('"')
+	return b.String()
+}
+
+// quoteSystemd quotes value for a systemd EnvironmentFile when needed.
+// Inside double quotes systemd only treats backslash, the quote itself, $
+// and ` specially; line breaks are kept literally.
+func quoteSystemd(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\r\n\"'\\$`#;") {
+		return value
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '"', '\\', '$', '`':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package dotenv
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMarshalFormat(t *testing.T) {
+	env := map[string]string{
+		"PORT":    "8080",
+		"MESSAGE": "say \"hi\"\n\tbye",
+		"COST":    "$5",
+	}
+
+	tests := map[Format]string{
+		FormatDotenv:  "COST=\"\\$5\"\nMESSAGE=\"say \\\"hi\\\"\\n\\tbye\"\nPORT=8080\n",
+		FormatJSON:    "{\n  \"COST\": \"$5\",\n  \"MESSAGE\": \"say \\\"hi\\\"\\n\\tbye\",\n  \"PORT\": \"8080\"\n}\n",
+		FormatYAML:    "\"COST\": \"$5\"\n\"MESSAGE\": \"say \\\"hi\\\"\\n\\tbye\"\n\"PORT\": \"8080\"\n",
+		FormatTOML:    "COST = \"$5\"\nMESSAGE = \"say \\\"hi\\\"\\n\\tbye\"\nPORT = \"8080\"\n",
+		FormatShell:   "export COST='$5'\nexport MESSAGE='say \"hi\"\n\tbye'\nexport PORT='8080'\n",
+		FormatSystemd: "COST=\"\\$5\"\nMESSAGE=\"say \\\"hi\\\"\n\tbye\"\nPORT=8080\n",
+	}
+
+	for format, expected := range tests {
+		got, err := MarshalFormat(env, format)
+		if err != nil {
+			t.Errorf("MarshalFormat(%s) failed: %v", format, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("MarshalFormat(%s):\nexpected %q\ngot      %q", format, expected, got)
+		}
+	}
+
+	// JSON output decodes back to the same map
+	out, err := MarshalFormat(env, FormatJSON)
+	if err != nil {
+		t.Fatalf("MarshalFormat(json) failed: %v", err)
+	}
+	var decoded map[string]string
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("Invalid JSON output: %v", err)
+	}
+	for key, value := range env {
+		if decoded[key] != value {
+			t.Errorf("JSON round trip mismatch for %s: %q", key, decoded[key])
+		}
+	}
+
+	// YAML 1.1 would read these keys unquoted as booleans and null
+	yamlKeys := map[string]string{"ON": "1", "NO": "2", "Y": "3", "NULL": "4"}
+	if got, _ := MarshalFormat(yamlKeys, FormatYAML); got != "\"NO\": \"2\"\n\"NULL\": \"4\"\n\"ON\": \"1\"\n\"Y\": \"3\"\n" {
+		t.Errorf("Expected quoted YAML keys, got %q", got)
+	}
+	if got, _ := MarshalFormat(map[string]string{"CTRL": "\x00\x1b"}, FormatTOML); got != "CTRL = \"\\u0000\\u001B\"\n" {
+		t.Errorf("Unexpected TOML control escapes: %q", got)
+	}
+	if _, err := MarshalFormat(map[string]string{"A": "x\ny"}, FormatDocker); err == nil {
+		t.Error("Expected error for multi-line value in Docker env-file")
+	}
+	if got, _ := MarshalFormat(map[string]string{"A": `"quoted" as is`}, FormatDocker); got != "A=\"quoted\" as is\n" {
+		t.Errorf("Docker env-file values should be verbatim, got %q", got)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
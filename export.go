@@ -0,0 +1,157 @@
+package dotenv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Shell identifies the syntax Export produces
+type Shell string
+
+// Supported shells. ShellBash output is also valid for zsh and POSIX sh.
+const (
+	ShellBash       Shell = "bash"
+	ShellZsh        Shell = "zsh"
+	ShellFish       Shell = "fish"
+	ShellPowerShell Shell = "powershell"
+	ShellCmd        Shell = "cmd"
+	ShellNushell    Shell = "nushell"
+)
+
+// ParseShell maps a shell name, including common aliases such as sh, pwsh
+// and nu, to a Shell
+func ParseShell(name string) (Shell, error) {
+	switch strings.ToLower(name) {
+	case "bash", "sh", "dash", "ksh":
+		return ShellBash, nil
+	case "zsh":
+		return ShellZsh, nil
+	case "fish":
+		return ShellFish, nil
+	case "powershell", "pwsh":
+		return ShellPowerShell, nil
+	case "cmd", "cmd.exe", "bat":
+		return ShellCmd, nil
+	case "nushell", "nu":
+		return ShellNushell, nil
+	}
+	return "", fmt.Errorf("unsupported shell %q", name)
+}
+
+// Export renders env as statements which set each variable when evaluated
+// by the given shell, e.g. with eval "$(dotenv export)". Keys are sorted
+// for consistent output.
+func Export(env map[string]string, shell Shell) (string, error) {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		line, err := exportLine(shell, key, env[key])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	return b.String(), nil
+}
+
+// exportLine renders a single assignment for shell
+func exportLine(shell Shell, key, value string) (string, error) {
+	switch shell {
+	case ShellBash, ShellZsh:
+		return "export " + key + "=" + quotePOSIX(value), nil
+	case ShellFish:
+		return "set -gx " + key + " " + quoteFish(value), nil
+	case ShellPowerShell:
+		return "$env:" + key + " = " + quotePowerShell(value), nil
+	case ShellCmd:
+		quoted, err := quoteCmd(value)
+		if err != nil {
+			return "", fmt.Errorf("cannot export %s for cmd: %w", key, err)
+		}
+		return `set "` + key + "=" + quoted + `"`, nil
+	case ShellNushell:
+		return "$env." + key + " = " + quoteNushell(value), nil
+	}
+	return "", fmt.Errorf("unsupported shell %q", shell)
+}
+
+// quotePOSIX single-quotes value; nothing is special inside single quotes
+// except the quote itself, which is written as
+//
+//	'\''
+func quotePOSIX(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// quoteFish single-quotes value; fish treats \\ and \' as escapes inside
+// single quotes
+func quoteFish(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "'", `\'`)
+	return "'" + value + "'"
+}
+
+// quotePowerShell single-quotes value, doubling embedded quotes. PowerShell
+// also treats the typographic quotes ‘ ’ ‚ ‛ as single quotes.
+func quotePowerShell(value string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range value {
+		switch r {
+		case '\'', '‘', '’', '‚', '‛':
+			b.WriteRune(r)
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// quoteCmd escapes value for use inside set "KEY=value" in a batch file.
+// cmd.exe has no way to represent line breaks in a set statement, and a
+// double quote would end the quoted text, letting & or | in the rest of the
+// value run commands.
+func quoteCmd(value string) (string, error) {
+	if strings.ContainsAny(value, "\r\n") {
+		return "", fmt.Errorf("value contains a line break")
+	}
+	if strings.ContainsRune(value, '"') {
+		return "", fmt.Errorf("value contains a double quote")
+	}
+	return strings.ReplaceAll(value, "%", "%%"), nil
+}
+
+// quoteNushell double-quotes value using nushell's escape sequences
+func quoteNushell(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u{%x}`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package dotenv
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+var exportValues = map[string]string{
+	"PLAIN":   "value",
+	"SINGLE":  `it's`,
+	"DOUBLE":  `say "hi"`,
+	"DOLLAR":  "$HOME and ${PATH} and $(whoami) and `id`",
+	"NEWLINE": "line1\nline2",
+	"BACKSL":  `C:\path\`,
+	"PERCENT": "100%",
+	"EMPTY":   "",
+}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		shell Shell
+		key   string
+		want  string
+	}{
+		{ShellBash, "SINGLE", `export SINGLE='it'\''s'`},
+		{ShellBash, "DOLLAR", "export DOLLAR='$HOME and ${PATH} and $(whoami) and `id`'"},
+		{ShellBash, "NEWLINE", "export NEWLINE='line1\nline2'"},
+		{ShellFish, "SINGLE", `set -gx SINGLE 'it\'s'`},
+		{ShellFish, "BACKSL", `set -gx BACKSL 'C:\\path\\'`},
+		{ShellPowerShell, "SINGLE", `$env:SINGLE = 'it''s'`},
+		{ShellPowerShell, "DOLLAR", "$env:DOLLAR = '$HOME and ${PATH} and $(whoami) and `id`'"},
+		{ShellCmd, "PERCENT", `set "PERCENT=100%%"`},
+		{ShellCmd, "DOLLAR", "set \"DOLLAR=$HOME and ${PATH} and $(whoami) and `id`\""},
+		{ShellNushell, "DOUBLE", `$env.DOUBLE = "say \"hi\""`},
+		{ShellNushell, "NEWLINE", `$env.NEWLINE = "line1\nline2"`},
+		{ShellNushell, "BACKSL", `$env.BACKSL = "C:\\path\\"`},
+	}
+
+	for _, tt := range tests {
+		out, err := Export(map[string]string{tt.key: exportValues[tt.key]}, tt.shell)
+		if err != nil {
+			t.Errorf("Export(%s, %s) failed: %v", tt.shell, tt.key, err)
+			continue
+		}
+		if got := strings.TrimSuffix(out, "\n"); got != tt.want {
+			t.Errorf("Export(%s, %s): expected %s, got %s", tt.shell, tt.key, tt.want, got)
+		}
+	}
+
+	if _, err := Export(map[string]string{"NEWLINE": "a\nb"}, ShellCmd); err == nil {
+		t.Error("Expected error exporting a line break for cmd")
+	}
+	// A quote would end set "K=..." early and run calc
+	for _, value := range []string{`a"&calc&"b`, `say "hi"`, `"`} {
+		if out, err := Export(map[string]string{"K": value}, ShellCmd); err == nil {
+			t.Errorf("Expected error exporting %s for cmd, got %s", value, out)
+		}
+	}
+	if _, err := Export(exportValues, Shell("tcsh")); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+}
+
+// TestExportEval evaluates the output in real shells that are installed
+func TestExportEval(t *testing.T) {
+	shells := []struct {
+		shell Shell
+		argv  []string
+	}{
+		{ShellBash, []string{"bash", "-c"}},
+		{ShellBash, []string{"sh", "-c"}},
+		{ShellZsh, []string{"zsh", "-c"}},
+		{ShellFish, []string{"fish", "-c"}},
+	}
+
+	for _, sh := range shells {
+		if _, err := exec.LookPath(sh.argv[0]); err != nil {
+			continue
+		}
+
+		script, err := Export(exportValues, sh.shell)
+		if err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+
+		for key, expected := range exportValues {
+			argv := append(sh.argv[1:], script+"printf '%s' \"$"+key+"\"")
+			out, err := exec.Command(sh.argv[0], argv...).Output()
+			if err != nil {
+				t.Errorf("%s: evaluating export failed: %v", sh.argv[0], err)
+				continue
+			}
+			if string(out) != expected {
+				t.Errorf("%s: expected %s=%q, got %q", sh.argv[0], key, expected, out)
+			}
+		}
+	}
+}
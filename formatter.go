@@ -0,0 +1,153 @@
+package dotenv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FormatterOptions configures Document.Format
+type FormatterOptions struct {
+	// Export writes every assignment with an export prefix; by default the
+	// prefix is removed
+	Export bool
+	// SortKeys sorts assignments within each group of consecutive lines not
+	// separated by blank lines or comments
+	SortKeys bool
+}
+
+// formatBlock is a comment or blank line, or a group of consecutive
+// assignments when entries is non-nil
+type formatBlock struct {
+	text    string
+	keys    []string
+	entries []string
+}
+
+// Format rewrites the document in canonical form: KEY=value with no spaces
+// around the separator, minimal quoting, inline comments separated by a
+// single space, trimmed comment lines, no repeated, leading or trailing
+// blank lines and a final newline. Empty values are left unquoted, and
+// values containing $ or line breaks keep their original quoting. Format
+// never changes what the document parses to and returns an error instead of
+// doing so.
+func (d *Document) Format(opts FormatterOptions) error {
+	want, err := NewParser().parseBytes([]byte(d.String()))
+	if err != nil {
+		return err
+	}
+
+	var blocks []*formatBlock
+	for _, n := range d.nodes {
+		last := len(blocks) - 1
+
+		if n.entry != nil {
+			if last < 0 || blocks[last].entries == nil {
+				blocks = append(blocks, &formatBlock{})
+				last++
+			}
+			blocks[last].keys = append(blocks[last].keys, n.entry.key)
+			blocks[last].entries = append(blocks[last].entries, formatEntry(n, opts.Export))
+			continue
+		}
+
+		text := strings.TrimSpace(n.raw)
+		if text == "" && (last < 0 || blocks[last].entries == nil && blocks[last].text == "") {
+			continue // Leading or repeated blank line
+		}
+		blocks = append(blocks, &formatBlock{text: text})
+	}
+	for len(blocks) > 0 && blocks[len(blocks)-1].entries == nil && blocks[len(blocks)-1].text == "" {
+		blocks = blocks[:len(blocks)-1]
+	}
+
+	if opts.SortKeys {
+		for _, block := range blocks {
+			if len(block.entries) < 2 {
+				continue
+			}
+
+			keys, entries := block.keys, block.entries
+			block.sort()
+			// Moving an assignment above a reference to it would change
+			// the expanded value
+			if got, err := NewParser().parseBytes([]byte(renderBlocks(blocks))); err != nil || !sameValues(got, want) {
+				block.keys, block.entries = keys, entries
+			}
+		}
+	}
+
+	formatted, err := parseDocument([]byte(renderBlocks(blocks)))
+	if err != nil {
+		return fmt.Errorf("formatting failed: %w", err)
+	}
+	got, err := NewParser().parseBytes([]byte(formatted.String()))
+	if err != nil {
+		return fmt.Errorf("formatting failed: %w", err)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			return fmt.Errorf("formatting would change the value of %s", key)
+		}
+	}
+
+	*d = *formatted
+	return nil
+}
+
+// sort orders the block's entries by key, keeping duplicates in order
+func (b *formatBlock) sort() {
+	order := make([]int, len(b.keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return b.keys[order[i]] < b.keys[order[j]] })
+
+	keys := make([]string, len(order))
+	entries := make([]string, len(order))
+	for i, j := range order {
+		keys[i], entries[i] = b.keys[j], b.entries[j]
+	}
+	b.keys, b.entries = keys, entries
+}
+
+// renderBlocks joins blocks into file content
+func renderBlocks(blocks []*formatBlock) string {
+	var b strings.Builder
+	for _, block := range blocks {
+		if block.entries == nil {
+			b.WriteString(block.text)
+			b.WriteByte('\n')
+			continue
+		}
+		for _, entry := range block.entries {
+			b.WriteString(entry)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// formatEntry renders an assignment node in canonical form
+func formatEntry(n *node, export bool) string {
+	e := n.entry
+
+	var value string
+	switch {
+	case e.value == "":
+	case strings.ContainsAny(e.value, "$\n"):
+		value = n.raw[e.valueStart:e.valueEnd]
+	default:
+		value = formatValue(e.value)
+	}
+
+	line := e.key + "=" + value
+	if export {
+		line = "export " + line
+	}
+
+	if comment := strings.TrimSpace(n.raw[e.valueEnd:]); strings.HasPrefix(comment, "#") {
+		line += " " + comment
+	}
+	return line
+}
@@ -0,0 +1,87 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocumentFormat(t *testing.T) {
+	input := "\n\n" +
+		"   # Database settings   \n" +
+		"export DB_HOST = localhost\n" +
+		"DB_PORT: '5432'   # default\n" +
+		"DB_NAME=\"app\"\n" +
+		"\n\n\n" +
+		"GREETING=hello world\n" +
+		"EMPTY=''\n" +
+		"URL=\"http://$DB_HOST:$DB_PORT\"\n" +
+		"CERT=\"line one\n" +
+		"line two\"\n" +
+		"\n\n"
+
+	doc, err := ParseDocument(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+	before, _ := Unmarshal(input)
+
+	if err := doc.Format(FormatterOptions{}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	expected := "# Database settings\n" +
+		"DB_HOST=localhost\n" +
+		"DB_PORT=5432 # default\n" +
+		"DB_NAME=app\n" +
+		"\n" +
+		"GREETING=\"hello world\"\n" +
+		"EMPTY=\n" +
+		"URL=\"http://$DB_HOST:$DB_PORT\"\n" +
+		"CERT=\"line one\n" +
+		"line two\"\n"
+	if got := doc.String(); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	after, err := Unmarshal(doc.String())
+	if err != nil || !reflect.DeepEqual(before, after) {
+		t.Errorf("Format changed values: %v -> %v (%v)", before, after, err)
+	}
+
+	// Formatting is idempotent
+	if err := doc.Format(FormatterOptions{}); err != nil || doc.String() != expected {
+		t.Errorf("Second Format changed the document:\n%s", doc.String())
+	}
+}
+
+func TestDocumentFormatOptions(t *testing.T) {
+	input := "# Group one\n" +
+		"ZED=1\n" +
+		"ALPHA=2\n" +
+		"MIDDLE=3\n" +
+		"# Group two references ALPHA_TWO before it is defined\n" +
+		"B=$ALPHA_TWO\n" +
+		"ALPHA_TWO=x\n"
+
+	doc, err := ParseDocument(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+
+	if err := doc.Format(FormatterOptions{SortKeys: true, Export: true}); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	// The second group is left alone: sorting it would change B
+	expected := "# Group one\n" +
+		"export ALPHA=2\n" +
+		"export MIDDLE=3\n" +
+		"export ZED=1\n" +
+		"# Group two references ALPHA_TWO before it is defined\n" +
+		"export B=$ALPHA_TWO\n" +
+		"export ALPHA_TWO=x\n"
+	if got := doc.String(); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
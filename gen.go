@@ -0,0 +1,296 @@
+package dotenv
+
+import (
+	"fmt"
+	"go/format"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// GoOptions configures GenerateGo
+type GoOptions struct {
+	// Package is the package clause of the generated file
+	Package string
+	// TypeName names the generated struct, "Config" by default; the load
+	// function is called Load followed by the type name
+	TypeName string
+	// Source names the schema file in comments, e.g. ".env.example"
+	Source string
+	// FileSecrets makes the load function read KEY from the file named by
+	// KEY_FILE when that is set, as Options.FileSecrets does
+	FileSecrets bool
+}
+
+// InferType returns the field's @type, or when none was given, the type
+// suggested by its default or example value
+func (f *Field) InferType() Type {
+	if f.typed {
+		return f.Type
+	}
+
+	value := f.Default
+	if value == "" {
+		value = f.Example
+	}
+	if value == "" {
+		return TypeString
+	}
+
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return TypeInt
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil && strings.ContainsAny(value, ".eE") {
+		return TypeFloat
+	}
+	if value == "true" || value == "false" {
+		return TypeBool
+	}
+	if _, err := time.ParseDuration(value); err == nil {
+		return TypeDuration
+	}
+	if u, err := url.Parse(value); err == nil && strings.Contains(value, "://") && u.Host != "" {
+		return TypeURL
+	}
+	return TypeString
+}
+
+// goTypes maps schema types to the Go types of generated fields
+var goTypes = map[Type]string{
+	TypeString:   "string",
+	TypeInt:      "int",
+	TypeFloat:    "float64",
+	TypeBool:     "bool",
+	TypeURL:      "*url.URL",
+	TypeDuration: "time.Duration",
+}
+
+// GenerateGo generates Go source declaring a struct with one typed field per
+// schema key, tagged with env:"KEY", and a function loading it from the
+// environment. Types come from @type annotations or are inferred from the
+// example values; see Field.InferType.
+func GenerateGo(schema *Schema, opts GoOptions) ([]byte, error) {
+	if opts.Package == "" {
+		return nil, fmt.Errorf("package name is required")
+	}
+	typeName := opts.TypeName
+	if typeName == "" {
+		typeName = "Config"
+	}
+	source := opts.Source
+	if source == "" {
+		source = "the schema"
+	}
+	lookup := strings.ToLower(typeName[:1]) + typeName[1:] + "Value"
+
+	// Packages are imported only when the generated code uses them
+	imports := map[string]bool{"errors": true, "io/fs": true, "os": true, "fmt": opts.FileSecrets, "strings": opts.FileSecrets}
+	names := make(map[string]string)
+
+	var decl, load strings.Builder
+	fmt.Fprintf(&decl, "// %s holds the variables described by %s.\n", typeName, source)
+	fmt.Fprintf(&decl, "type %s struct {\n", typeName)
+
+	for i, f := range schema.Fields {
+		name := goFieldName(f.Key)
+		if name == "" {
+			return nil, fmt.Errorf("cannot derive a Go name from key %s", f.Key)
+		}
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("keys %s and %s both map to field %s", other, f.Key, name)
+		}
+		names[name] = f.Key
+
+		typ := f.InferType()
+		if i > 0 {
+			decl.WriteString("\n")
+		}
+		if f.Description != "" {
+			fmt.Fprintf(&decl, "\t// %s\n", f.Description)
+		} else {
+			fmt.Fprintf(&decl, "\t// %s holds %s.\n", name, f.Key)
+		}
+		fmt.Fprintf(&decl, "\t%s %s `env:%q`\n", name, goTypes[typ], f.Key)
+
+		def := f.Default
+		if f.IsSecret() {
+			def = "" // Keep example secrets out of the binary
+		}
+		if opts.FileSecrets {
+			fmt.Fprintf(&load, "\tif v, err := %s(%q, %q); err != nil {\n\t\treturn nil, err\n\t} else if v != \"\" {\n", lookup, f.Key, def)
+		} else {
+			fmt.Fprintf(&load, "\tif v := %s(%q, %q); v != \"\" {\n", lookup, f.Key, def)
+		}
+		load.WriteString(goParse(typ, "c."+name, f.Key, f.IsSecret(), imports))
+		load.WriteString("\t}")
+		if f.Required {
+			imports["fmt"], imports["strings"] = true, true
+			fmt.Fprintf(&load, " else {\n\t\tmissing = append(missing, %q)\n\t}", f.Key)
+		}
+		load.WriteString("\n")
+	}
+	decl.WriteString("}\n")
+
+	var b strings.Builder
+	b.WriteString("// Code generated by dotenv gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\nimport (\n", opts.Package)
+	for _, path := range sortedKeys(imports) {
+		fmt.Fprintf(&b, "\t%q\n", path)
+	}
+	b.WriteString("\n\t\"github.com/mew-sh/dotenv\"\n)\n\n")
+	b.WriteString(decl.String())
+
+	fmt.Fprintf(&b, `
+// Load%[1]s loads the given .env files (.env by default) without
+// overriding variables that are already set, then reads %[1]s from the
+// environment. Unset variables take their defaults from %[2]s. Missing
+// .env files are skipped; later files take precedence over earlier ones.
+func Load%[1]s(filenames ...string) (*%[1]s, error) {
+	if len(filenames) == 0 {
+		filenames = []string{dotenv.DefaultEnvFile}
+	}
+	var existing []string
+	for _, filename := range filenames {
+		if _, err := os.Stat(filename); !errors.Is(err, fs.ErrNotExist) {
+			existing = append(existing, filename)
+		}
+	}
+	if len(existing) > 0 {
+		if err := dotenv.Load(existing...); err != nil {
+			return nil, err
+		}
+	}
+
+	c := &%[1]s{}
+`, typeName, source)
+	if strings.Contains(load.String(), "missing = ") {
+		b.WriteString("\tvar missing []string\n")
+	}
+	b.WriteString(load.String())
+	if strings.Contains(load.String(), "missing = ") {
+		b.WriteString("\tif len(missing) > 0 {\n\t\treturn nil, fmt.Errorf(\"missing required variables: %s\", strings.Join(missing, \", \"))\n\t}\n")
+	}
+	b.WriteString("\treturn c, nil\n}\n")
+
+	if opts.FileSecrets {
+		fmt.Fprintf(&b, `
+// %[1]s returns the contents of the file named by key_FILE, the value of
+// key, or def when neither is set
+func %[1]s(key, def string) (string, error) {
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		if os.Getenv(key) != "" {
+			return "", fmt.Errorf("both %%s and %%s_FILE are set", key, key)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("%%s_FILE: %%w", key, err)
+		}
+		return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+	}
+	if v := os.Getenv(key); v != "" {
+		return v, nil
+	}
+	return def, nil
+}
+`, lookup)
+	} else {
+		fmt.Fprintf(&b, `
+// %[1]s returns the value of key, or def when it is unset or empty
+func %[1]s(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+`, lookup)
+	}
+
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go code: %w", err)
+	}
+	return src, nil
+}
+
+// goParse returns the statements converting v to typ and storing it in dst.
+// Errors name the invalid value unless the key is secret.
+func goParse(typ Type, dst, key string, secret bool, imports map[string]bool) string {
+	fail := func(what string) string {
+		imports["fmt"] = true
+		if secret {
+			return fmt.Sprintf("\t\t\treturn nil, fmt.Errorf(\"%s: invalid %s\")\n", key, what)
+		}
+		return fmt.Sprintf("\t\t\treturn nil, fmt.Errorf(\"%s: invalid %s %%q\", v)\n", key, what)
+	}
+
+	switch typ {
+	case TypeInt:
+		imports["strconv"] = true
+		return "\t\tn, err := strconv.Atoi(v)\n\t\tif err != nil {\n" + fail("integer") + "\t\t}\n\t\t" + dst + " = n\n"
+	case TypeFloat:
+		imports["strconv"] = true
+		return "\t\tn, err := strconv.ParseFloat(v, 64)\n\t\tif err != nil {\n" + fail("number") + "\t\t}\n\t\t" + dst + " = n\n"
+	case TypeBool:
+		imports["strings"] = true
+		return "\t\tswitch strings.ToLower(v) {\n\t\tcase \"true\", \"1\", \"yes\", \"on\":\n\t\t\t" + dst +
+			" = true\n\t\tcase \"false\", \"0\", \"no\", \"off\":\n\t\tdefault:\n" + fail("boolean") + "\t\t}\n"
+	case TypeDuration:
+		imports["time"] = true
+		return "\t\td, err := time.ParseDuration(v)\n\t\tif err != nil {\n" + fail("duration") + "\t\t}\n\t\t" + dst + " = d\n"
+	case TypeURL:
+		imports["net/url"] = true
+		return "\t\tu, err := url.Parse(v)\n\t\tif err != nil {\n" + fail("URL") + "\t\t}\n\t\t" + dst + " = u\n"
+	}
+	return "\t\t" + dst + " = v\n"
+}
+
+// goInitialisms are written in upper case in generated names, as Go style
+// asks
+var goInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "AWS": true, "CPU": true, "CSS": true, "DB": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "JWT": true, "OS": true, "RAM": true, "RPC": true, "SDK": true,
+	"SMTP": true, "SQL": true, "SSH": true, "SSL": true, "TCP": true, "TLS": true, "TTL": true,
+	"UDP": true, "UI": true, "UID": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// goFieldName converts an environment key such as DATABASE_URL to an
+// exported Go name such as DatabaseURL. Initialisms followed by a version
+// number stay upper case too, so HTTP2_ENABLED becomes HTTP2Enabled.
+func goFieldName(key string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(key, "_") {
+		if word == "" {
+			continue
+		}
+		upper := strings.ToUpper(word)
+		if goInitialisms[strings.TrimRight(upper, "0123456789")] {
+			b.WriteString(upper)
+			continue
+		}
+		lower := []rune(strings.ToLower(word))
+		lower[0] = unicode.ToUpper(lower[0])
+		b.WriteString(string(lower))
+	}
+
+	name := b.String()
+	if name != "" && !unicode.IsLetter([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// sortedKeys returns the keys of m set to true, in order
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key, ok := range m {
+		if ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,297 @@
+package dotenv
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFieldInferType(t *testing.T) {
+	tests := map[string]Type{
+		"A=8080\n":                        TypeInt,
+		"A=0.5\n":                         TypeFloat,
+		"A=true\n":                        TypeBool,
+		"A=30s\n":                         TypeDuration,
+		"A=postgres://localhost/app\n":    TypeURL,
+		"A=hello\n":                       TypeString,
+		"A=\n":                            TypeString,
+		"# @example 42\nA=\n":             TypeInt,
+		"# @type string\nA=8080\n":        TypeString,
+		"# @type duration\nA=\n":          TypeDuration,
+		"A=1e3\n":                         TypeFloat,
+		"A=yes\n":                         TypeString,
+		"A=http//not-a-url.example.com\n": TypeString,
+	}
+
+	for input, expected := range tests {
+		schema, err := ParseSchema(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("ParseSchema(%q) failed: %v", input, err)
+		}
+		if got := schema.Fields[0].InferType(); got != expected {
+			t.Errorf("InferType for %q: expected %s, got %s", input, expected, got)
+		}
+	}
+}
+
+func TestGoFieldName(t *testing.T) {
+	tests := map[string]string{
+		"PORT":          "Port",
+		"DATABASE_URL":  "DatabaseURL",
+		"API_KEY":       "APIKey",
+		"_PRIVATE__ID":  "PrivateID",
+		"lower_case":    "LowerCase",
+		"HTTP2_ENABLED": "HTTP2Enabled",
+		"X_9":           "X9",
+		"API_URL":       "APIURL",
+		"DB_ID":         "DBID",
+		"USER_ID2":      "UserID2",
+		"IPV6_ADDR":     "Ipv6Addr",
+		"OAUTH2_TOKEN":  "Oauth2Token",
+		"S3_BUCKET":     "S3Bucket",
+	}
+
+	for key, expected := range tests {
+		if got := goFieldName(key); got != expected {
+			t.Errorf("goFieldName(%q): expected %q, got %q", key, expected, got)
+		}
+	}
+}
+
+func TestGenerateGo(t *testing.T) {
+	schema, err := ParseSchema(strings.NewReader(schemaExample))
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+
+	src, err := GenerateGo(schema, GoOptions{Package: "config", Source: ".env.example"})
+	if err != nil {
+		t.Fatalf("GenerateGo failed: %v", err)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "config_gen.go", src, 0); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, src)
+	}
+
+	code := string(src)
+	for _, want := range []string{
+		"// Code generated by dotenv gen; DO NOT EDIT.",
+		"package config",
+		"// Port the HTTP server listens on\n\tPort int `env:\"PORT\"`",
+		"DatabaseURL *url.URL `env:\"DATABASE_URL\"`",
+		"Timeout time.Duration `env:\"TIMEOUT\"`",
+		"Debug bool `env:\"DEBUG\"`",
+		"func LoadConfig(filenames ...string) (*Config, error)",
+		`configValue("PORT", "8080")`,
+		`missing = append(missing, "DATABASE_URL")`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Expected generated code to contain %q:\n%s", want, code)
+		}
+	}
+
+	withFiles, err := GenerateGo(schema, GoOptions{Package: "config", FileSecrets: true})
+	if err != nil {
+		t.Fatalf("GenerateGo failed: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "config_gen.go", withFiles, 0); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, withFiles)
+	}
+	if !strings.Contains(string(withFiles), `os.Getenv(key + "_FILE")`) || strings.Contains(code, "_FILE") {
+		t.Error("Expected _FILE support only with FileSecrets")
+	}
+
+	if _, err := GenerateGo(schema, GoOptions{}); err == nil {
+		t.Error("Expected error without a package name")
+	}
+
+	clash, _ := ParseSchema(strings.NewReader("API_KEY=\nAPI__KEY=\n"))
+	if _, err := GenerateGo(clash, GoOptions{Package: "config"}); err == nil {
+		t.Error("Expected error for clashing field names")
+	}
+}
+
+// stubImporter type-checks generated code against the standard library and
+// a stub of this package, which only needs Load and DefaultEnvFile
+type stubImporter struct {
+	std types.Importer
+}
+
+func (i stubImporter) Import(path string) (*types.Package, error) {
+	if path != "github.com/mew-sh/dotenv" {
+		return i.std.Import(path)
+	}
+	pkg := types.NewPackage(path, "dotenv")
+	errType := types.Universe.Lookup("error").Type()
+	params := types.NewTuple(types.NewVar(token.NoPos, pkg, "filenames", types.NewSlice(types.Typ[types.String])))
+	results := types.NewTuple(types.NewVar(token.NoPos, pkg, "", errType))
+	sig := types.NewSignatureType(nil, nil, nil, params, results, true)
+	pkg.Scope().Insert(types.NewFunc(token.NoPos, pkg, "Load", sig))
+	pkg.Scope().Insert(types.NewConst(token.NoPos, pkg, "DefaultEnvFile", types.Typ[types.UntypedString], constant.MakeString(DefaultEnvFile)))
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+func TestGenerateGoTypeChecks(t *testing.T) {
+	fset := token.NewFileSet()
+	conf := types.Config{Importer: stubImporter{std: importer.ForCompiler(fset, "source", nil)}}
+
+	schemas := map[string]string{
+		"plain strings":   "NAME=app\nGREETING=\n",
+		"no fields":       "",
+		"required string": "# @required\nNAME=\n",
+		"bool only":       "DEBUG=false\n",
+		"int only":        "PORT=8080\n",
+		"url and time":    "DATABASE_URL=postgres://localhost/app\nTIMEOUT=30s\nRATIO=0.5\n",
+		"full example":    schemaExample,
+	}
+
+	for name, input := range schemas {
+		schema, err := ParseSchema(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("%s: ParseSchema failed: %v", name, err)
+		}
+		for _, fileSecrets := range []bool{false, true} {
+			src, err := GenerateGo(schema, GoOptions{Package: "config", FileSecrets: fileSecrets})
+			if err != nil {
+				t.Fatalf("%s: GenerateGo failed: %v", name, err)
+			}
+			file, err := parser.ParseFile(fset, "config_gen.go", src, 0)
+			if err != nil {
+				t.Fatalf("%s: generated code does not parse: %v\n%s", name, err, src)
+			}
+			if _, err := conf.Check("config", fset, []*ast.File{file}, nil); err != nil {
+				t.Errorf("%s (FileSecrets %t): generated code does not type-check: %v\n%s", name, fileSecrets, err, src)
+			}
+		}
+	}
+}
+
+const loaderMain = `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	c, err := LoadConfig(os.Args[1:]...)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+	fmt.Println(c.APIURL, c.Port)
+}
+`
+
+// TestGenerateGoLoader builds the generated loader against this module and
+// runs it on several sets of files
+func TestGenerateGoLoader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a program")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := ParseSchema(strings.NewReader("# @required @type url\nAPI_URL=\nPORT=8080\n# @type int @secret\nPIN=\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := GenerateGo(schema, GoOptions{Package: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod := t.TempDir()
+	files := map[string]string{
+		"go.mod":        "module loadertest\n\ngo 1.24\n\nrequire github.com/mew-sh/dotenv v0.0.0\n\nreplace github.com/mew-sh/dotenv => " + root + "\n",
+		"config_gen.go": string(src),
+		"main.go":       loaderMain,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(mod, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bin := filepath.Join(mod, "loader")
+	build := exec.Command(goTool, "build", "-o", bin, ".")
+	build.Dir = mod
+	build.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("building the generated loader failed: %v\n%s", err, out)
+	}
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		env   []string
+		args  []string
+		want  string
+	}{
+		{
+			name:  "first file missing",
+			files: map[string]string{".env.local": "API_URL=http://api\nPORT=9\n"},
+			args:  []string{".env", ".env.local"},
+			want:  "http://api 9\n",
+		},
+		{
+			name:  "later files take precedence",
+			files: map[string]string{".env": "API_URL=http://api\nPORT=1\n", ".env.local": "PORT=2\n"},
+			args:  []string{".env", ".env.local"},
+			want:  "http://api 2\n",
+		},
+		{
+			name:  "environment wins",
+			files: map[string]string{".env": "API_URL=http://api\nPORT=1\n"},
+			env:   []string{"PORT=3"},
+			want:  "http://api 3\n",
+		},
+		{
+			name: "no files",
+			args: []string{".env", ".env.local"},
+			want: "error: missing required variables: API_URL\n",
+		},
+		{
+			name:  "secret values are not reported",
+			files: map[string]string{".env": "API_URL=http://api\nPIN=hunter2\n"},
+			want:  "error: PIN: invalid integer\n",
+		},
+		{
+			name:  "other values are",
+			files: map[string]string{".env": "API_URL=http://api\nPORT=eighty\n"},
+			want:  "error: PORT: invalid integer \"eighty\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			cmd := exec.Command(bin, tt.args...)
+			cmd.Dir = dir
+			cmd.Env = append([]string{"PATH=" + os.Getenv("PATH")}, tt.env...)
+			out, _ := cmd.CombinedOutput()
+			if string(out) != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseINI reads an INI file. Keys inside a [section] are prefixed with the
+// section name and an underscore, and the result is normalized into a
+// variable name as by ParseJSON: characters that are not valid in variable
+// names become underscores, a leading digit is prefixed with one and letters
+// are upper-cased, so host in [database] becomes DATABASE_HOST. Comment
+// lines start with ; or #, keys are separated from values by = or :, and
+// values may be wrapped in single or double quotes. When two keys normalize
+// to the same name, the later one wins.
+func ParseINI(reader io.Reader) (map[string]string, error) {
+	src, err := readAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading input: %w", err)
+	}
+
+	result := make(map[string]string)
+	prefix := ""
+
+	for i, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("parse error on line %d: unterminated section header", i+1)
+			}
+			prefix = strings.TrimSpace(line[1:end])
+			if prefix != "" {
+				prefix += "_"
+			}
+			continue
+		}
+
+		sep := strings.IndexAny(line, "=:")
+		if sep <= 0 {
+			return nil, fmt.Errorf("parse error on line %d: expected key = value", i+1)
+		}
+
+		key := strings.TrimSpace(line[:sep])
+		result[normalizeKey(prefix+key)] = iniValue(strings.TrimSpace(line[sep+1:]))
+	}
+
+	return result, nil
+}
+
+// iniValue strips matching quotes, or an inline ; or # comment from an
+// unquoted value
+func iniValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	for i := 1; i < len(value); i++ {
+		if (value[i] == ';' || value[i] == '#') && isBlank(value[i-1]) {
+			return strings.TrimSpace(value[:i])
+		}
+	}
+	return value
+}
+
+// quoteINI quotes a value when reading it back would otherwise change it
+func quoteINI(value string) (string, error) {
+	if strings.ContainsAny(value, "\r\n") {
+		return "", fmt.Errorf("value contains a line break")
+	}
+
+	trimmed := strings.TrimSpace(value)
+	needsQuotes := trimmed != value || strings.ContainsAny(value, ";#")
+	if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+		needsQuotes = true
+	}
+	if !needsQuotes {
+		return value, nil
+	}
+
+	if !strings.ContainsRune(value, '"') {
+		return `"` + value + `"`, nil
+	}
+	if !strings.ContainsRune(value, '\'') {
+		return "'" + value + "'", nil
+	}
+	return "", fmt.Errorf("value contains both quote characters")
+}
@@ -0,0 +1,92 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseINI(t *testing.T) {
+	input := `; global settings
+name = demo
+
+[database]
+host = localhost
+port: 5432 ; default port
+password = "p;ss word "
+# comment
+[cache]
+url='redis://localhost # not a comment'
+ttl=60
+[my-app.v2]
+log.level = debug
+`
+
+	env, err := ParseINI(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseINI failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"NAME":                "demo",
+		"DATABASE_HOST":       "localhost",
+		"DATABASE_PORT":       "5432",
+		"DATABASE_PASSWORD":   "p;ss word ",
+		"CACHE_URL":           "redis://localhost # not a comment",
+		"CACHE_TTL":           "60",
+		"MY_APP_V2_LOG_LEVEL": "debug",
+	}
+
+	if len(env) != len(expected) {
+		t.Errorf("Expected %d keys, got %d: %v", len(expected), len(env), env)
+	}
+	for key, value := range expected {
+		if env[key] != value {
+			t.Errorf("Expected %s=%q, got %q", key, value, env[key])
+		}
+	}
+}
+
+func TestParseINIErrors(t *testing.T) {
+	tests := map[string]string{
+		"unterminated section": "[database\nhost=x\n",
+		"missing separator":    "[database]\nhost\n",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseINI(strings.NewReader(input))
+			if err == nil || !strings.Contains(err.Error(), "parse error on line") {
+				t.Errorf("Expected a parse error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestINIRoundTrip(t *testing.T) {
+	env := map[string]string{
+		"PLAIN":    "value",
+		"COMMENT":  "a ; b",
+		"SPACES":   "  padded  ",
+		"QUOTED":   `"starts with quote`,
+		"DATABASE": "postgres://u:p@h/db",
+	}
+
+	content, err := MarshalFormat(env, FormatINI)
+	if err != nil {
+		t.Fatalf("MarshalFormat failed: %v", err)
+	}
+
+	parsed, err := ParseINI(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseINI failed: %v\n%s", err, content)
+	}
+	for key, value := range env {
+		if parsed[key] != value {
+			t.Errorf("Round trip of %s: expected %q, got %q", key, value, parsed[key])
+		}
+	}
+
+	if _, err := MarshalFormat(map[string]string{"K": "a\nb"}, FormatINI); err == nil {
+		t.Error("Expected error for multi-line INI value")
+	}
+}
@@ -0,0 +1,179 @@
+package dotenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// KeyCase selects how ParseJSON transforms flattened key names
+type KeyCase int
+
+const (
+	// CaseUpper converts keys to upper case (DATABASE_HOST); the default
+	CaseUpper KeyCase = iota
+	// CaseLower converts keys to lower case (database_host)
+	CaseLower
+	// CasePreserve keeps keys as they appear in the JSON document
+	CasePreserve
+)
+
+// JSONOptions controls how ParseJSON flattens nested JSON. The zero value
+// joins nested names with "_", upper-cases them and indexes arrays.
+type JSONOptions struct {
+	// Separator joins the names of nested objects; "_" if empty
+	Separator string
+	// KeyCase transforms the flattened names
+	KeyCase KeyCase
+	// JoinArrays encodes an array as a single value joined with
+	// ArraySeparator instead of one KEY_0, KEY_1, ... entry per element
+	JoinArrays bool
+	// ArraySeparator joins array elements when JoinArrays is set; "," if empty
+	ArraySeparator string
+}
+
+// ParseJSON reads a JSON object and flattens it into environment variables.
+// Nested objects produce KEY_SUBKEY names, numbers and booleans are
+// stringified as written, null becomes an empty value and arrays are
+// either indexed (KEY_0) or joined, see JSONOptions. Names are normalized
+// as by ParseProperties and ParseINI: characters that are not valid in
+// variable names are replaced with underscores, a leading digit is
+// prefixed with one and letters are upper-cased unless opts.KeyCase says
+// otherwise.
+func ParseJSON(reader io.Reader, opts JSONOptions) (map[string]string, error) {
+	if opts.Separator == "" {
+		opts.Separator = "_"
+	}
+	if opts.ArraySeparator == "" {
+		opts.ArraySeparator = ","
+	}
+
+	dec := json.NewDecoder(reader)
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	object, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid JSON: top-level value must be an object")
+	}
+
+	f := &flattener{opts: opts, result: make(map[string]string)}
+	for name, value := range object {
+		if err := f.flatten(f.key("", name), value); err != nil {
+			return nil, err
+		}
+	}
+
+	return f.result, nil
+}
+
+// flattener accumulates the variables produced from a JSON document
+type flattener struct {
+	opts   JSONOptions
+	result map[string]string
+}
+
+// flatten adds the variables for value under key
+func (f *flattener) flatten(key string, value any) error {
+	switch v := value.(type) {
+	case map[string]any:
+		for name, child := range v {
+			if err := f.flatten(f.key(key, name), child); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case []any:
+		if f.opts.JoinArrays {
+			parts := make([]string, len(v))
+			for i, elem := range v {
+				parts[i] = jsonScalar(elem)
+			}
+			return f.set(key, strings.Join(parts, f.opts.ArraySeparator))
+		}
+		for i, elem := range v {
+			if err := f.flatten(f.key(key, strconv.Itoa(i)), elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return f.set(key, jsonScalar(value))
+}
+
+// set records a variable, rejecting names produced twice
+func (f *flattener) set(key, value string) error {
+	if _, exists := f.result[key]; exists {
+		return fmt.Errorf("duplicate key %s after flattening", key)
+	}
+	f.result[key] = value
+	return nil
+}
+
+// key joins a nested name onto prefix, sanitizing it into a valid variable
+// name and applying the case transform
+func (f *flattener) key(prefix, name string) string {
+	key := sanitizeKey(name, prefix == "")
+	if prefix != "" {
+		key = prefix + f.opts.Separator + key
+	}
+
+	switch f.opts.KeyCase {
+	case CaseUpper:
+		return strings.ToUpper(key)
+	case CaseLower:
+		return strings.ToLower(key)
+	}
+	return key
+}
+
+// normalizeKey turns name into the variable name ParseJSON, ParseProperties
+// and ParseINI use for it: sanitized as by sanitizeKey and upper-cased
+func normalizeKey(name string) string {
+	return strings.ToUpper(sanitizeKey(name, true))
+}
+
+// sanitizeKey replaces the characters of name that are not valid in
+// variable names with underscores. A leading digit is prefixed with one when
+// name starts the variable name.
+func sanitizeKey(name string, start bool) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case start && i == 0 && c >= '0' && c <= '9':
+			b.WriteByte('_')
+			b.WriteByte(c)
+		case isKeyChar(c):
+			b.WriteByte(c)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// jsonScalar stringifies a decoded JSON value. Objects and arrays, which
+// only reach here as elements of joined arrays, are encoded as compact JSON.
+func jsonScalar(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,125 @@
+package dotenv
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const nestedJSON = `{
+  "database": {"host": "localhost", "port": 5432, "ssl": true, "password": null},
+  "api-keys": ["a", "b"],
+  "servers": [{"name": "one"}, {"name": "two"}],
+  "ratio": 1.50,
+  "9lives": "cat"
+}`
+
+func TestParseJSON(t *testing.T) {
+	env, err := ParseJSON(strings.NewReader(nestedJSON), JSONOptions{})
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"DATABASE_HOST":     "localhost",
+		"DATABASE_PORT":     "5432",
+		"DATABASE_SSL":      "true",
+		"DATABASE_PASSWORD": "",
+		"API_KEYS_0":        "a",
+		"API_KEYS_1":        "b",
+		"SERVERS_0_NAME":    "one",
+		"SERVERS_1_NAME":    "two",
+		"RATIO":             "1.50",
+		"_9LIVES":           "cat",
+	}
+
+	if len(env) != len(expected) {
+		t.Errorf("Expected %d keys, got %d: %v", len(expected), len(env), env)
+	}
+	for key, value := range expected {
+		if actual, ok := env[key]; !ok || actual != value {
+			t.Errorf("Expected %s=%q, got %q (present: %t)", key, value, actual, ok)
+		}
+	}
+}
+
+func TestParseJSONOptions(t *testing.T) {
+	env, err := ParseJSON(strings.NewReader(nestedJSON), JSONOptions{
+		Separator:      "__",
+		KeyCase:        CaseLower,
+		JoinArrays:     true,
+		ArraySeparator: ";",
+	})
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+
+	if env["database__host"] != "localhost" {
+		t.Errorf("Expected custom separator and lower case, got %v", env)
+	}
+	if env["api_keys"] != "a;b" {
+		t.Errorf("Expected joined array, got %q", env["api_keys"])
+	}
+	if env["servers"] != `{"name":"one"};{"name":"two"}` {
+		t.Errorf("Expected joined objects as JSON, got %q", env["servers"])
+	}
+
+	if _, err := ParseJSON(strings.NewReader(`{"a_b": 1, "a": {"b": 2}}`), JSONOptions{}); err == nil {
+		t.Error("Expected error for keys colliding after flattening")
+	}
+	if _, err := ParseJSON(strings.NewReader(`[1, 2]`), JSONOptions{}); err == nil {
+		t.Error("Expected error for non-object document")
+	}
+}
+
+func TestReadJSONFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(filename, []byte(nestedJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := Read(filename)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if env["DATABASE_PORT"] != "5432" {
+		t.Errorf("Expected flattened JSON values, got %v", env)
+	}
+
+	// Flattened values can be written back out as .env
+	content, err := Marshal(env)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(content, "DATABASE_HOST=localhost") {
+		t.Errorf("Unexpected marshaled content:\n%s", content)
+	}
+}
+
+func TestKeyNormalizationIsShared(t *testing.T) {
+	// The same names read from JSON, .properties and INI give the same keys
+	inputs := map[string]func() (map[string]string, error){
+		"json": func() (map[string]string, error) {
+			return ParseJSON(strings.NewReader(`{"db": {"url": "x", "max-conns": "5"}, "2fa": "on"}`), JSONOptions{})
+		},
+		"properties": func() (map[string]string, error) {
+			return ParseProperties(strings.NewReader("db.url=x\ndb.max-conns=5\n2fa=on\n"))
+		},
+		"ini": func() (map[string]string, error) {
+			return ParseINI(strings.NewReader("2fa=on\n[db]\nurl=x\nmax-conns=5\n"))
+		},
+	}
+	expected := map[string]string{"DB_URL": "x", "DB_MAX_CONNS": "5", "_2FA": "on"}
+
+	for name, parse := range inputs {
+		env, err := parse()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !maps.Equal(env, expected) {
+			t.Errorf("%s: expected %v, got %v", name, expected, env)
+		}
+	}
+}
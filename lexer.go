@@ -0,0 +1,483 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// statement is a single KEY=value assignment produced by the lexer
+type statement struct {
+	key       string
+	value     string
+	separator byte
+	export    bool
+	// quote is the quote character around the value, 0 when unquoted
+	quote byte
+	line  int
+	// start and end delimit the statement in the source, from the first
+	// character of the key (or export prefix) to the end of its last line;
+	// valueStart and valueEnd delimit the raw value including quotes
+	start, end           int
+	valueStart, valueEnd int
+}
+
+// lexer is a single-pass, byte-level tokenizer for .env content. Keys,
+// separators, quoting, comments and variable references are all handled in
+// one scan over the input, so there is no per-line length limit and values
+// are decoded without intermediate strings.
+type lexer struct {
+	parser *Parser
+	src    []byte
+	pos    int
+	line   int
+	// buf is reused to decode values that need escape or expansion processing
+	buf []byte
+}
+
+// utf8BOM is skipped when it prefixes the input
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+func newLexer(parser *Parser, src []byte) *lexer {
+	l := &lexer{parser: parser, src: src, line: 1}
+	if len(src) >= len(utf8BOM) && string(src[:len(utf8BOM)]) == string(utf8BOM) {
+		l.pos = len(utf8BOM)
+	}
+	return l
+}
+
+// next scans up to and including the next assignment, skipping blank lines
+// and comments. It reports false once the input is exhausted. On error the
+// returned statement carries the line the assignment started on.
+func (l *lexer) next() (statement, bool, error) {
+	for {
+		l.skipWhitespace()
+		if l.pos >= len(l.src) {
+			return statement{}, false, nil
+		}
+
+		if l.src[l.pos] == '#' {
+			l.skipLine()
+			continue
+		}
+
+		stmt := statement{line: l.line}
+		err := l.scanAssignment(&stmt)
+		return stmt, true, err
+	}
+}
+
+// scanAssignment scans `[export] KEY [=:] value [# comment]`
+func (l *lexer) scanAssignment(stmt *statement) error {
+	start := l.pos
+	stmt.start = start
+
+	keyStart, keyEnd := l.scanKey()
+	if keyStart == keyEnd {
+		return l.invalidLine(start)
+	}
+
+	// "export KEY=value"; a key literally named export is still allowed
+	if string(l.src[keyStart:keyEnd]) == "export" && l.pos < len(l.src) && isBlank(l.src[l.pos]) {
+		l.skipBlanks()
+		if s, e := l.scanKey(); s != e {
+			keyStart, keyEnd = s, e
+			stmt.export = true
+		} else {
+			l.pos = keyEnd
+		}
+	}
+
+	l.skipBlanks()
+	if l.pos >= len(l.src) || (l.src[l.pos] != '=' && l.src[l.pos] != ':') {
+		return l.invalidLine(start)
+	}
+	stmt.key = string(l.src[keyStart:keyEnd])
+	stmt.separator = l.src[l.pos]
+	l.pos++
+	sepEnd := l.pos
+
+	if l.parser.strict && stmt.separator != '=' {
+		return fmt.Errorf("key %s uses %q separator, expected \"=\"", stmt.key, stmt.separator)
+	}
+
+	l.skipBlanks()
+	stmt.valueStart = l.pos
+	stmt.valueEnd = l.pos
+
+	var err error
+	if l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '"':
+			stmt.quote = '"'
+			stmt.value, err = l.scanDoubleQuoted(&stmt.valueEnd)
+		case '\'', '`':
+			stmt.quote = l.src[l.pos]
+			stmt.value, err = l.scanLiteralQuoted(stmt.quote, &stmt.valueEnd)
+		default:
+			stmt.value, err = l.scanUnquoted(&stmt.valueEnd)
+		}
+	}
+	stmt.end = l.pos
+	if stmt.valueEnd == stmt.valueStart {
+		// Anchor empty values to the separator so edits keep the spacing
+		// before any inline comment
+		stmt.valueStart, stmt.valueEnd = sepEnd, sepEnd
+	}
+	if err != nil && l.parser.strict {
+		err = fmt.Errorf("key %s: %w", stmt.key, err)
+	}
+
+	return err
+}
+
+// scanKey scans an identifier matching [A-Za-z_][A-Za-z0-9_]* and returns
+// its bounds; start == end when no key is present
+func (l *lexer) scanKey() (int, int) {
+	start := l.pos
+	if l.pos >= len(l.src) || !isKeyStart(l.src[l.pos]) {
+		return start, start
+	}
+
+	l.pos++
+	for l.pos < len(l.src) && isKeyChar(l.src[l.pos]) {
+		l.pos++
+	}
+	return start, l.pos
+}
+
+// scanUnquoted scans a bare value up to the end of the line or an inline
+// comment, trimming surrounding whitespace. The end of the raw value is
+// stored in valueEnd.
+func (l *lexer) scanUnquoted(valueEnd *int) (string, error) {
+	start := l.pos
+	hasVars := false
+
+scan:
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '\n', '#':
+			break scan
+		case '$':
+			hasVars = true
+		}
+		l.pos++
+	}
+
+	var strictErr error
+	if l.parser.strict {
+		comment := l.pos < len(l.src) && l.src[l.pos] == '#'
+		strictErr = checkStrictUnquoted(l.src[start:l.pos], comment)
+	}
+
+	end := l.pos
+	for end > start && isSpace(l.src[end-1]) {
+		end--
+	}
+	raw := l.src[start:end]
+	*valueEnd = end
+	l.skipLine()
+
+	if strictErr != nil {
+		return "", strictErr
+	}
+
+	if !hasVars || !l.parser.expandVars {
+		return string(raw), nil
+	}
+
+	l.buf = l.buf[:0]
+	run := 0
+	for i := 0; i < len(raw); {
+		if raw[i] != '$' {
+			i++
+			continue
+		}
+		l.buf = append(l.buf, raw[run:i]...)
+		var n int
+		l.buf, n = l.appendVariable(l.buf, raw[i:])
+		i += n
+		run = i
+	}
+	l.buf = append(l.buf, raw[run:]...)
+
+	return string(l.buf), nil
+}
+
+// checkStrictUnquoted checks the raw span of an unquoted value, up to the
+// end of the line or a '#', for syntax that Docker env-files and shells read
+// differently: quotes, whitespace and inline comments, which Docker keeps as
+// part of the value
+func checkStrictUnquoted(span []byte, comment bool) error {
+	if !comment && len(span) > 0 && span[len(span)-1] == '\r' {
+		span = span[:len(span)-1] // CRLF line ending
+	}
+
+	for i, c := range span {
+		if (c == '"' || c == '\'' || c == '`') && (i == 0 || span[i-1] != '\\') {
+			return fmt.Errorf("unquoted value contains unescaped %c quote", c)
+		}
+	}
+
+	trimmed := len(span)
+	for trimmed > 0 && isSpace(span[trimmed-1]) {
+		trimmed--
+	}
+	switch {
+	case comment && trimmed > 0:
+		return errors.New("inline comment after unquoted value, quote the value")
+	case trimmed < len(span) && !comment:
+		return errors.New("unquoted value has trailing whitespace")
+	}
+	for _, c := range span[:trimmed] {
+		if isSpace(c) {
+			return errors.New("unquoted value contains whitespace")
+		}
+	}
+	return nil
+}
+
+// scanDoubleQuoted scans a double-quoted value, which may span lines,
+// processing escape sequences and variable references. The offset just past
+// the closing quote is stored in valueEnd.
+func (l *lexer) scanDoubleQuoted(valueEnd *int) (string, error) {
+	l.pos++ // Opening quote
+	l.buf = l.buf[:0]
+	run := l.pos
+
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == '"':
+			l.buf = append(l.buf, l.src[run:l.pos]...)
+			l.pos++
+			*valueEnd = l.pos
+			return string(l.buf), l.scanTrailer('"')
+		case c == '\\' && l.pos+1 < len(l.src):
+			l.buf = append(l.buf, l.src[run:l.pos]...)
+			l.buf = l.appendEscape(l.buf)
+			run = l.pos
+		case c == '$' && l.parser.expandVars:
+			l.buf = append(l.buf, l.src[run:l.pos]...)
+			var n int
+			l.buf, n = l.appendVariable(l.buf, l.src[l.pos:])
+			l.pos += n
+			run = l.pos
+		default:
+			if c == '\n' {
+				l.line++
+			}
+			l.pos++
+		}
+	}
+
+	return "", errors.New("unterminated \" quote")
+}
+
+// scanLiteralQuoted scans a single- or backtick-quoted value, which may span
+// lines and is taken literally (no escapes, no expansion). The offset just
+// past the closing quote is stored in valueEnd.
+func (l *lexer) scanLiteralQuoted(quote byte, valueEnd *int) (string, error) {
+	l.pos++ // Opening quote
+	start := l.pos
+
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case quote:
+			value := string(l.src[start:l.pos])
+			l.pos++
+			*valueEnd = l.pos
+			return value, l.scanTrailer(quote)
+		case '\n':
+			l.line++
+		}
+		l.pos++
+	}
+
+	return "", fmt.Errorf("unterminated %c quote", quote)
+}
+
+// scanTrailer checks that only whitespace or a comment follows a closing quote
+func (l *lexer) scanTrailer(quote byte) error {
+	l.skipBlanks()
+	if l.pos >= len(l.src) || l.src[l.pos] == '\n' || l.src[l.pos] == '\r' || l.src[l.pos] == '#' {
+		l.skipLine()
+		return nil
+	}
+	return fmt.Errorf("unexpected characters after closing %c quote", quote)
+}
+
+// appendEscape appends the decoded form of the escape sequence at l.pos and
+// advances past it. Unknown or malformed escapes are kept literally.
+func (l *lexer) appendEscape(dst []byte) []byte {
+	c := l.src[l.pos+1]
+	l.pos += 2
+
+	switch c {
+	case 'n':
+		return append(dst, '\n')
+	case 'r':
+		return append(dst, '\r')
+	case 't':
+		return append(dst, '\t')
+	case '0':
+		return append(dst, 0)
+	case 'a':
+		return append(dst, '\a')
+	case 'b':
+		return append(dst, '\b')
+	case 'f':
+		return append(dst, '\f')
+	case 'v':
+		return append(dst, '\v')
+	case '\\', '"', '\'', '$':
+		return append(dst, c)
+	case '\n':
+		// Line continuation
+		l.line++
+		return dst
+	case '\r':
+		if l.pos < len(l.src) && l.src[l.pos] == '\n' {
+			l.pos++
+			l.line++
+			return dst
+		}
+	case 'x':
+		if v, ok := parseHex(l.src[l.pos:], 2); ok {
+			l.pos += 2
+			return append(dst, byte(v))
+		}
+	case 'u':
+		if v, ok := parseHex(l.src[l.pos:], 4); ok {
+			l.pos += 4
+			return utf8.AppendRune(dst, rune(v))
+		}
+	case 'U':
+		if v, ok := parseHex(l.src[l.pos:], 8); ok && v <= unicode.MaxRune {
+			l.pos += 8
+			return utf8.AppendRune(dst, rune(v))
+		}
+	}
+
+	// Unknown escape, keep the backslash
+	return append(dst, '\\', c)
+}
+
+// parseHex decodes exactly n hexadecimal digits from the start of s
+func parseHex(s []byte, n int) (uint32, bool) {
+	if len(s) < n {
+		return 0, false
+	}
+
+	var v uint32
+	for _, c := range s[:n] {
+		switch {
+		case c >= '0' && c <= '9':
+			c -= '0'
+		case c >= 'a' && c <= 'f':
+			c -= 'a' - 10
+		case c >= 'A' && c <= 'F':
+			c -= 'A' - 10
+		default:
+			return 0, false
+		}
+		v = v<<4 | uint32(c)
+	}
+	return v, true
+}
+
+// appendVariable expands the $VAR or ${VAR} reference at the start of s and
+// returns the extended dst together with the number of bytes consumed. A '$'
+// that does not start a valid reference is kept literally.
+func (l *lexer) appendVariable(dst, s []byte) ([]byte, int) {
+	if len(s) > 1 && s[1] == '{' {
+		for i := 2; i < len(s); i++ {
+			switch s[i] {
+			case '}':
+				if i == 2 {
+					return append(dst, '$'), 1
+				}
+				return append(dst, l.parser.lookup(s[2:i])...), i + 1
+			case '"', '\n':
+				return append(dst, '$'), 1
+			}
+		}
+		return append(dst, '$'), 1
+	}
+
+	if len(s) < 2 || !isKeyStart(s[1]) {
+		return append(dst, '$'), 1
+	}
+
+	end := 2
+	for end < len(s) && isKeyChar(s[end]) {
+		end++
+	}
+	return append(dst, l.parser.lookup(s[1:end])...), end
+}
+
+// invalidLine reports the line starting at start as malformed. The line is
+// never quoted, as a value can hold a credential whatever its key is called;
+// only a leading identifier is named, unless it looks like a credential
+// itself.
+func (l *lexer) invalidLine(start int) error {
+	l.pos = start
+	l.skipLine()
+
+	end := start
+	for end < l.pos && isKeyChar(l.src[end]) {
+		end++
+	}
+	key := string(l.src[start:end])
+	if key == "" || !isKeyStart(key[0]) {
+		return fmt.Errorf("invalid line format")
+	}
+	if _, _, secret := scanValue(key, ScanOptions{}); secret {
+		return fmt.Errorf("invalid line format")
+	}
+	return fmt.Errorf("invalid line format for %s", key)
+}
+
+// skipWhitespace skips blanks and newlines, counting lines
+func (l *lexer) skipWhitespace() {
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '\n':
+			l.line++
+		case ' ', '\t', '\r', '\v', '\f':
+		default:
+			return
+		}
+		l.pos++
+	}
+}
+
+// skipBlanks skips spaces and tabs within the current line
+func (l *lexer) skipBlanks() {
+	for l.pos < len(l.src) && isBlank(l.src[l.pos]) {
+		l.pos++
+	}
+}
+
+// skipLine advances to the newline ending the current line
+func (l *lexer) skipLine() {
+	for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+		l.pos++
+	}
+}
+
+func isKeyStart(c byte) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+func isKeyChar(c byte) bool {
+	return isKeyStart(c) || (c >= '0' && c <= '9')
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\v' || c == '\f'
+}
@@ -0,0 +1,354 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Names of the rules checked by Lint
+const (
+	// RuleDuplicateKey reports keys assigned more than once
+	RuleDuplicateKey = "duplicate-key"
+	// RuleLowercaseKey reports keys containing lower-case letters
+	RuleLowercaseKey = "lowercase-key"
+	// RuleTrailingWhitespace reports lines ending in spaces or tabs
+	RuleTrailingWhitespace = "trailing-whitespace"
+	// RuleUnquotedSpaces reports unquoted values containing whitespace
+	RuleUnquotedSpaces = "unquoted-spaces"
+	// RuleUndefinedVariable reports $VAR references to variables that are
+	// neither assigned earlier in the file nor set in the environment
+	RuleUndefinedVariable = "undefined-variable"
+	// RuleNotInExample reports keys missing from the example file
+	RuleNotInExample = "not-in-example"
+)
+
+// LintRule describes a rule checked by Lint
+type LintRule struct {
+	Name        string
+	Description string
+	// Fixable reports whether FixLint can correct the rule's findings
+	Fixable bool
+}
+
+// LintOptions configures Lint and FixLint
+type LintOptions struct {
+	// Disable lists the names of rules to skip
+	Disable []string
+	// Example holds the variables of the reference file, usually
+	// .env.example. The not-in-example rule is skipped when it is nil.
+	Example map[string]string
+}
+
+// Diagnostic is a single problem found by Lint
+type Diagnostic struct {
+	// File is left empty by Lint for callers to fill in
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	Fixable bool   `json:"fixable"`
+}
+
+func (d Diagnostic) String() string {
+	if d.File == "" {
+		return fmt.Sprintf("line %d: %s (%s)", d.Line, d.Message, d.Rule)
+	}
+	return fmt.Sprintf("%s:%d: %s (%s)", d.File, d.Line, d.Message, d.Rule)
+}
+
+// finding is a diagnostic together with the edit that corrects it, if any
+type finding struct {
+	Diagnostic
+	fix func(d *Document)
+}
+
+// lintRules lists every rule in the order it is checked
+var lintRules = []struct {
+	LintRule
+	check func(d *Document, opts LintOptions) []finding
+}{
+	{LintRule{RuleDuplicateKey, "keys assigned more than once", true}, checkDuplicateKeys},
+	{LintRule{RuleLowercaseKey, "keys containing lower-case letters", false}, checkLowercaseKeys},
+	{LintRule{RuleTrailingWhitespace, "lines ending in spaces or tabs", true}, checkTrailingWhitespace},
+	{LintRule{RuleUnquotedSpaces, "unquoted values containing whitespace", true}, checkUnquotedSpaces},
+	{LintRule{RuleUndefinedVariable, "references to variables that are not defined", false}, checkUndefinedVariables},
+	{LintRule{RuleNotInExample, "keys missing from the example file", false}, checkNotInExample},
+}
+
+// LintRules returns the rules checked by Lint
+func LintRules() []LintRule {
+	rules := make([]LintRule, len(lintRules))
+	for i, r := range lintRules {
+		rules[i] = r.LintRule
+	}
+	return rules
+}
+
+// Lint checks a document for common mistakes and returns the problems found,
+// ordered by line
+func Lint(doc *Document, opts LintOptions) ([]Diagnostic, error) {
+	findings, err := lint(doc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	diags := make([]Diagnostic, len(findings))
+	for i, f := range findings {
+		diags[i] = f.Diagnostic
+	}
+	return diags, nil
+}
+
+// FixLint corrects the fixable problems Lint reports and returns how many
+// were fixed. A fix is only kept when the document still parses to the same
+// values, so fixes never change what Read returns.
+func FixLint(doc *Document, opts LintOptions) (int, error) {
+	findings, err := lint(doc, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	want, err := NewParser().parseBytes([]byte(doc.String()))
+	if err != nil {
+		return 0, err
+	}
+
+	fixed := 0
+	for _, f := range findings {
+		if f.fix == nil {
+			continue
+		}
+
+		restore := doc.snapshot()
+		f.fix(doc)
+		got, err := NewParser().parseBytes([]byte(doc.String()))
+		if err != nil || !sameValues(got, want) {
+			restore()
+			continue
+		}
+		fixed++
+	}
+
+	// Renumber lines after removals
+	if fixed > 0 {
+		reparsed, err := parseDocument([]byte(doc.String()))
+		if err != nil {
+			return fixed, err
+		}
+		*doc = *reparsed
+	}
+	return fixed, nil
+}
+
+// lint runs the enabled rules over doc
+func lint(doc *Document, opts LintOptions) ([]finding, error) {
+	disabled := make(map[string]bool)
+	for _, name := range opts.Disable {
+		known := false
+		for _, r := range lintRules {
+			known = known || r.Name == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown lint rule %q", name)
+		}
+		disabled[name] = true
+	}
+
+	var findings []finding
+	for _, r := range lintRules {
+		if !disabled[r.Name] {
+			findings = append(findings, r.check(doc, opts)...)
+		}
+	}
+
+	// A stable sort keeps rule order within a line
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Line < findings[j].Line })
+	return findings, nil
+}
+
+// newFinding creates a finding for rule on line n
+func newFinding(rule string, line int, fix func(d *Document), format string, args ...any) finding {
+	return finding{
+		Diagnostic: Diagnostic{
+			Line:    line,
+			Rule:    rule,
+			Message: fmt.Sprintf(format, args...),
+			Fixable: fix != nil,
+		},
+		fix: fix,
+	}
+}
+
+func checkDuplicateKeys(doc *Document, _ LintOptions) []finding {
+	var findings []finding
+	previous := make(map[string]*node)
+	first := make(map[string]int)
+
+	for _, n := range doc.nodes {
+		if n.entry == nil {
+			continue
+		}
+		key := n.entry.key
+
+		if prev, ok := previous[key]; ok {
+			// The later assignment wins, so dropping the earlier one keeps
+			// the value
+			findings = append(findings, newFinding(RuleDuplicateKey, n.line,
+				func(d *Document) { d.remove(prev) },
+				"duplicate key %s (first defined on line %d)", key, first[key]))
+		} else {
+			first[key] = n.line
+		}
+		previous[key] = n
+	}
+	return findings
+}
+
+func checkLowercaseKeys(doc *Document, _ LintOptions) []finding {
+	var findings []finding
+	for _, n := range doc.nodes {
+		if n.entry != nil && strings.ToUpper(n.entry.key) != n.entry.key {
+			findings = append(findings, newFinding(RuleLowercaseKey, n.line, nil,
+				"key %s should be upper case", n.entry.key))
+		}
+	}
+	return findings
+}
+
+func checkTrailingWhitespace(doc *Document, _ LintOptions) []finding {
+	var findings []finding
+	for _, n := range doc.nodes {
+		// Earlier lines of a multi-line node are inside a quoted value
+		last := n.raw[strings.LastIndexByte(n.raw, '\n')+1:]
+		last = strings.TrimSuffix(last, "\r")
+		if last == strings.TrimRight(last, " \t") {
+			continue
+		}
+
+		findings = append(findings, newFinding(RuleTrailingWhitespace, n.line+strings.Count(n.raw, "\n"),
+			func(*Document) { trimTrailingWhitespace(n) },
+			"trailing whitespace"))
+	}
+	return findings
+}
+
+// trimTrailingWhitespace removes blanks from the end of a node's last line,
+// keeping a carriage return
+func trimTrailingWhitespace(n *node) {
+	raw, cr := strings.CutSuffix(n.raw, "\r")
+	raw = strings.TrimRight(raw, " \t")
+	if n.entry != nil && len(raw) < n.entry.valueEnd {
+		return
+	}
+	if cr {
+		raw += "\r"
+	}
+	n.raw = raw
+}
+
+func checkUnquotedSpaces(doc *Document, _ LintOptions) []finding {
+	var findings []finding
+	for _, n := range doc.nodes {
+		e := n.entry
+		if e == nil || e.quote != 0 || !strings.ContainsAny(e.value, " \t") {
+			continue
+		}
+
+		// Quoting would escape $ and stop expansion
+		var fix func(*Document)
+		if !strings.Contains(e.value, "$") {
+			fix = func(d *Document) { d.setValue(n, e.value) }
+		}
+		findings = append(findings, newFinding(RuleUnquotedSpaces, n.line, fix,
+			"value of %s contains whitespace and should be quoted", e.key))
+	}
+	return findings
+}
+
+func checkUndefinedVariables(doc *Document, _ LintOptions) []finding {
+	var findings []finding
+	defined := make(map[string]bool)
+
+	for _, n := range doc.nodes {
+		e := n.entry
+		if e == nil {
+			continue
+		}
+
+		if e.quote == 0 || e.quote == '"' {
+			raw := n.raw[e.valueStart:e.valueEnd]
+			for _, name := range variableReferences(raw, e.quote == '"') {
+				if _, ok := os.LookupEnv(name); !defined[name] && !ok {
+					findings = append(findings, newFinding(RuleUndefinedVariable, n.line, nil,
+						"%s references undefined variable %s", e.key, name))
+				}
+			}
+		}
+		defined[e.key] = true
+	}
+	return findings
+}
+
+// variableReferences returns the names referenced by $VAR and ${VAR} in a
+// raw value; escapes are honoured when the value is double-quoted
+func variableReferences(raw string, escapes bool) []string {
+	var names []string
+	for i := 0; i < len(raw); i++ {
+		switch {
+		case raw[i] == '\\' && escapes:
+			i++
+		case raw[i] == '$' && i+1 < len(raw):
+			start, end := i+1, i+1
+			if raw[start] == '{' {
+				brace := strings.IndexByte(raw[start:], '}')
+				if brace < 0 {
+					continue
+				}
+				start, end = start+1, start+brace
+			} else {
+				for end < len(raw) && (isKeyChar(raw[end]) && (end > start || isKeyStart(raw[end]))) {
+					end++
+				}
+			}
+			if end > start {
+				names = append(names, raw[start:end])
+			}
+			i = end - 1
+		}
+	}
+	return names
+}
+
+func checkNotInExample(doc *Document, opts LintOptions) []finding {
+	if opts.Example == nil {
+		return nil
+	}
+
+	var findings []finding
+	seen := make(map[string]bool)
+	for _, n := range doc.nodes {
+		if e := n.entry; e != nil && !seen[e.key] {
+			seen[e.key] = true
+			if _, ok := opts.Example[e.key]; !ok {
+				findings = append(findings, newFinding(RuleNotInExample, n.line, nil,
+					"key %s is not in the example file", e.key))
+			}
+		}
+	}
+	return findings
+}
+
+// sameValues reports whether two parse results are equal
+func sameValues(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const lintInput = "# Database\n" +
+	"DB_HOST=localhost\n" +
+	"db_port=5432 \n" +
+	"GREETING=hello world\n" +
+	"URL=http://$DB_HOST:${DB_PORT}/$MISSING_VAR_FOR_LINT\n" +
+	"ESCAPED=\"\\$NOT_A_REFERENCE\"\n" +
+	"LITERAL='$ALSO_NOT_A_REFERENCE'\n" +
+	"DB_HOST=db.internal\n" +
+	"PATH_COPY=\"$PATH with spaces\"\n"
+
+func lintRuleLines(diags []Diagnostic) map[string][]int {
+	result := make(map[string][]int)
+	for _, d := range diags {
+		result[d.Rule] = append(result[d.Rule], d.Line)
+	}
+	return result
+}
+
+func TestLint(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(lintInput))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+
+	example := map[string]string{"DB_HOST": "", "db_port": "", "URL": ""}
+	diags, err := Lint(doc, LintOptions{Example: example})
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+
+	expected := map[string][]int{
+		RuleDuplicateKey:       {8},
+		RuleLowercaseKey:       {3},
+		RuleTrailingWhitespace: {3},
+		RuleUnquotedSpaces:     {4},
+		RuleUndefinedVariable:  {5, 5},
+		RuleNotInExample:       {4, 6, 7, 9},
+	}
+	if got := lintRuleLines(diags); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	for i := 1; i < len(diags); i++ {
+		if diags[i].Line < diags[i-1].Line {
+			t.Errorf("Diagnostics not ordered by line: %v", diags)
+		}
+	}
+
+	diags, err = Lint(doc, LintOptions{Disable: []string{RuleNotInExample, RuleUndefinedVariable, RuleLowercaseKey}})
+	if err != nil {
+		t.Fatalf("Lint failed: %v", err)
+	}
+	if got := lintRuleLines(diags); len(got[RuleUndefinedVariable]) != 0 || len(got[RuleLowercaseKey]) != 0 {
+		t.Errorf("Disabled rules reported: %v", diags)
+	}
+
+	if _, err := Lint(doc, LintOptions{Disable: []string{"no-such-rule"}}); err == nil {
+		t.Error("Expected error for unknown rule")
+	}
+}
+
+func TestFixLint(t *testing.T) {
+	input := "A=1  \n" +
+		"# comment\t\n" +
+		"B=two words # note\n" +
+		"A=2\n" +
+		"C=$A and more\n" +
+		"A=3\n"
+
+	doc, err := ParseDocument(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+	before, _ := Unmarshal(input)
+
+	fixed, err := FixLint(doc, LintOptions{})
+	if err != nil {
+		t.Fatalf("FixLint failed: %v", err)
+	}
+
+	// The first A is redundant; removing
+	// the second would change C
+	expected := "# comment\n" +
+		"B=\"two words\" # note\n" +
+		"A=2\n" +
+		"C=$A and more\n" +
+		"A=3\n"
+	if got := doc.String(); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+	if fixed != 4 {
+		t.Errorf("Expected 4 fixes, got %d", fixed)
+	}
+
+	after, err := Unmarshal(doc.String())
+	if err != nil || !reflect.DeepEqual(before, after) {
+		t.Errorf("Fixes changed values: %v -> %v (%v)", before, after, err)
+	}
+
+	diags, _ := Lint(doc, LintOptions{})
+	for _, d := range diags {
+		if d.Fixable && d.Rule != RuleDuplicateKey {
+			t.Errorf("Fixable diagnostic left: %v", d)
+		}
+	}
+	if len(diags) == 0 || diags[0].Line != 4 {
+		t.Errorf("Expected renumbered diagnostics, got %v", diags)
+	}
+}
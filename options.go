@@ -0,0 +1,123 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Options configures ReadWithOptions and LoadWithOptions
+type Options struct {
+	// Strict parses files with a strict parser; see NewStrictParser
+	Strict bool
+	// Override lets LoadWithOptions replace variables that are already set
+	Override bool
+	// FileSecrets follows the Docker and Kubernetes convention of passing
+	// secrets as files: for every KEY_FILE variable, KEY is set to the
+	// contents of the named file without its trailing newline. It is an
+	// error for the file to be unreadable or for KEY to be set as well.
+	FileSecrets bool
+}
+
+// fileSuffix marks variables naming a secret file
+const fileSuffix = "_FILE"
+
+// ReadWithOptions behaves like Read, configured by opts. With FileSecrets,
+// only KEY_FILE variables defined in the files are considered.
+func ReadWithOptions(opts Options, filenames ...string) (map[string]string, error) {
+	newParser := NewParser
+	if opts.Strict {
+		newParser = NewStrictParser
+	}
+
+	env, err := read(newParser, filenames...)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.FileSecrets {
+		lookup := func(key string) string { return env[key] }
+		if err := readFileSecrets(env, keysOf(env), lookup); err != nil {
+			return nil, err
+		}
+	}
+	return env, nil
+}
+
+// LoadWithOptions behaves like Load, configured by opts. With FileSecrets,
+// KEY_FILE variables already present in the environment are honoured too.
+func LoadWithOptions(opts Options, filenames ...string) error {
+	fileOpts := opts
+	fileOpts.FileSecrets = false
+	env, err := ReadWithOptions(fileOpts, filenames...)
+	if err != nil {
+		return err
+	}
+
+	// effective returns the value key will have once env is applied
+	effective := func(key string) string {
+		if value, ok := env[key]; ok && (opts.Override || os.Getenv(key) == "") {
+			return value
+		}
+		return os.Getenv(key)
+	}
+
+	if opts.FileSecrets {
+		candidates := keysOf(env)
+		for _, kv := range os.Environ() {
+			if key, _, _ := strings.Cut(kv, "="); strings.HasSuffix(key, fileSuffix) {
+				if _, ok := env[key]; !ok {
+					candidates = append(candidates, key)
+				}
+			}
+		}
+		if err := readFileSecrets(env, candidates, effective); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range env {
+		if opts.Override || os.Getenv(key) == "" {
+			if err := os.Setenv(key, value); err != nil {
+				return fmt.Errorf("failed to set environment variable %s: %w", key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// readFileSecrets sets KEY in env for every KEY_FILE among candidates whose
+// value, as returned by lookup, names a file
+func readFileSecrets(env map[string]string, candidates []string, lookup func(string) string) error {
+	sort.Strings(candidates)
+
+	for _, fileKey := range candidates {
+		key, ok := strings.CutSuffix(fileKey, fileSuffix)
+		path := lookup(fileKey)
+		if !ok || key == "" || path == "" {
+			continue
+		}
+
+		if lookup(key) != "" {
+			return fmt.Errorf("both %s and %s are set", key, fileKey)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", fileKey, err)
+		}
+		env[key] = trimNewline(string(data))
+	}
+	return nil
+}
+
+// keysOf returns the keys of env
+func keysOf(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	return keys
+}
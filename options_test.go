@@ -0,0 +1,87 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadWithOptionsFileSecrets(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "db_password")
+	if err := os.WriteFile(secret, []byte("hunter2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	envFile := createTempEnvFile(t, "DB_PASSWORD_FILE="+secret+"\nEMPTY_FILE=\n_FILE=ignored\n")
+
+	env, err := ReadWithOptions(Options{FileSecrets: true}, envFile)
+	if err != nil {
+		t.Fatalf("ReadWithOptions failed: %v", err)
+	}
+	if env["DB_PASSWORD"] != "hunter2" {
+		t.Errorf("Expected DB_PASSWORD=hunter2, got %q", env["DB_PASSWORD"])
+	}
+	if _, ok := env["EMPTY"]; ok {
+		t.Error("Empty KEY_FILE should be ignored")
+	}
+
+	// Without the option KEY_FILE is an ordinary variable
+	env, _ = ReadWithOptions(Options{}, envFile)
+	if _, ok := env["DB_PASSWORD"]; ok {
+		t.Error("DB_PASSWORD set without FileSecrets")
+	}
+
+	both := createTempEnvFile(t, "DB_PASSWORD=inline\nDB_PASSWORD_FILE="+secret+"\n")
+	if _, err := ReadWithOptions(Options{FileSecrets: true}, both); err == nil ||
+		!strings.Contains(err.Error(), "both DB_PASSWORD and DB_PASSWORD_FILE are set") {
+		t.Errorf("Expected conflict error, got %v", err)
+	}
+
+	missing := createTempEnvFile(t, "TOKEN_FILE="+filepath.Join(dir, "missing")+"\n")
+	if _, err := ReadWithOptions(Options{FileSecrets: true}, missing); err == nil ||
+		!strings.Contains(err.Error(), "TOKEN_FILE") {
+		t.Errorf("Expected unreadable file error, got %v", err)
+	}
+
+	strict := createTempEnvFile(t, "A=1\nA=2\n")
+	if _, err := ReadWithOptions(Options{Strict: true}, strict); err == nil {
+		t.Error("Expected strict mode error")
+	}
+}
+
+func TestLoadWithOptionsFileSecrets(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "api_token")
+	if err := os.WriteFile(secret, []byte("from-file\r\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hide KEY_FILE variables of the environment the tests run in
+	for _, kv := range os.Environ() {
+		if key, _, _ := strings.Cut(kv, "="); strings.HasSuffix(key, "_FILE") {
+			t.Setenv(key, "")
+		}
+	}
+
+	// A KEY_FILE set by the container platform rather than a .env file
+	t.Setenv("TEST_API_TOKEN_FILE", secret)
+	t.Setenv("TEST_API_TOKEN", "")
+	envFile := createTempEnvFile(t, "TEST_OTHER=1\n")
+
+	if err := LoadWithOptions(Options{FileSecrets: true}, envFile); err != nil {
+		t.Fatalf("LoadWithOptions failed: %v", err)
+	}
+	if got := os.Getenv("TEST_API_TOKEN"); got != "from-file" {
+		t.Errorf("Expected TEST_API_TOKEN=from-file, got %q", got)
+	}
+	os.Unsetenv("TEST_OTHER")
+
+	// A value already in the environment conflicts with the file
+	t.Setenv("TEST_API_TOKEN", "from-env")
+	if err := LoadWithOptions(Options{FileSecrets: true}, envFile); err == nil {
+		t.Error("Expected conflict error")
+	}
+	os.Unsetenv("TEST_OTHER")
+}
@@ -1,28 +1,26 @@
 package dotenv
 
 import (
-	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
-	"regexp"
 	"strconv"
 	"strings"
 )
 
-var (
-	// Regular expressions for parsing
-	lineRegex      = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*[=:]\s*(.*)$`)
-	exportRegex    = regexp.MustCompile(`^\s*export\s+([A-Za-z_][A-Za-z0-9_]*)\s*[=:]\s*(.*)$`)
-	expandVarRegex = regexp.MustCompile(`\$\{([^}]+)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
-)
-
 // Parser handles the parsing of .env file content
 type Parser struct {
 	// expandVars determines if variable expansion should be performed
 	expandVars bool
+	// strict rejects duplicate keys, colon separators, unmatched quotes
+	// and unquoted values containing whitespace
+	strict bool
 	// env holds the currently parsed environment variables for expansion
 	env map[string]string
+	// key decrypts enc:v1: values; it is loaded on first use
+	key       []byte
+	keyLoaded bool
 }
 
 // NewParser creates a new parser with default settings
@@ -41,191 +39,96 @@ func NewParserWithOptions(expandVars bool) *Parser {
 	}
 }
 
-// Parse reads from an io.Reader and parses the .env content
-func (p *Parser) Parse(reader io.Reader) (map[string]string, error) {
-	result := make(map[string]string)
-	p.env = result // For variable expansion
-
-	scanner := bufio.NewScanner(reader)
-	lineNumber := 0
-
-	for scanner.Scan() {
-		lineNumber++
-		line := scanner.Text()
-
-		// Skip empty lines and comments
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		key, value, err := p.parseLine(line)
-		if err != nil {
-			return nil, fmt.Errorf("parse error on line %d: %w", lineNumber, err)
-		}
-
-		if key != "" {
-			if p.expandVars {
-				value = p.expandVariables(value, result)
-			}
-			result[key] = value
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading input: %w", err)
+// NewStrictParser creates a parser that rejects syntax which is not portable
+// to Docker env-files and POSIX shells: duplicate keys, `KEY: value` colon
+// separators, unmatched quotes, and unquoted values containing whitespace
+// (trailing whitespace included), unescaped quotes or followed by an inline
+// comment.
+func NewStrictParser() *Parser {
+	return &Parser{
+		expandVars: true,
+		strict:     true,
+		env:        make(map[string]string),
 	}
-
-	return result, nil
 }
 
-// parseLine parses a single line and returns key, value, and any error
-func (p *Parser) parseLine(line string) (string, string, error) {
-	// Remove inline comments (but not those inside quotes)
-	line = p.removeInlineComment(line)
-
-	// Handle export prefix
-	if matches := exportRegex.FindStringSubmatch(line); matches != nil {
-		key := matches[1]
-		value := strings.TrimSpace(matches[2])
-		parsedValue, err := p.parseValue(value)
-		return key, parsedValue, err
-	}
-
-	// Handle regular key=value or key:value
-	if matches := lineRegex.FindStringSubmatch(line); matches != nil {
-		key := matches[1]
-		value := strings.TrimSpace(matches[2])
-		parsedValue, err := p.parseValue(value)
-		return key, parsedValue, err
-	}
-
-	// If line doesn't match any pattern and isn't empty, it's an error
-	if strings.TrimSpace(line) != "" {
-		return "", "", fmt.Errorf("invalid line format: %q", line)
+// Parse reads from an io.Reader and parses the .env content. Values
+// encrypted with EncryptValue are decrypted when LoadKey finds a key and
+// left as they are otherwise.
+func (p *Parser) Parse(reader io.Reader) (map[string]string, error) {
+	src, err := readAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading input: %w", err)
 	}
 
-	return "", "", nil
+	return p.parseBytes(src)
 }
 
-// parseValue parses a value, handling quotes and escaping
-func (p *Parser) parseValue(value string) (string, error) {
-	value = strings.TrimSpace(value)
+// parseBytes parses .env content that is already in memory
+func (p *Parser) parseBytes(src []byte) (map[string]string, error) {
+	result := make(map[string]string)
+	p.env = result // For variable expansion
 
-	if value == "" {
-		return "", nil
+	// seen records the line each key was first defined on (strict mode only)
+	var seen map[string]int
+	if p.strict {
+		seen = make(map[string]int)
 	}
 
-	// Handle quoted values
-	if len(value) >= 2 {
-		if (value[0] == '"' && value[len(value)-1] == '"') ||
-			(value[0] == '\'' && value[len(value)-1] == '\'') {
-			quote := value[0]
-			inner := value[1 : len(value)-1]
+	lex := newLexer(p, src)
+	for {
+		stmt, ok, err := lex.next()
+		if err != nil {
+			return nil, fmt.Errorf("parse error on line %d: %w", stmt.line, err)
+		}
+		if !ok {
+			break
+		}
 
-			if quote == '"' {
-				// Double quotes: process escape sequences
-				return p.unescapeDoubleQuoted(inner), nil
-			} else {
-				// Single quotes: literal value (no escape processing)
-				return inner, nil
+		if IsEncrypted(stmt.value) {
+			if stmt.value, err = p.decrypt(stmt.key, stmt.value); err != nil {
+				return nil, fmt.Errorf("parse error on line %d: cannot decrypt %s: %w", stmt.line, stmt.key, err)
 			}
 		}
-	}
-
-	// Unquoted value - trim trailing whitespace and remove trailing comments
-	return strings.TrimSpace(value), nil
-}
-
-// removeInlineComment removes inline comments while preserving those inside quotes
-func (p *Parser) removeInlineComment(line string) string {
-	inQuotes := false
-	quoteChar := byte(0)
 
-	for i := 0; i < len(line); i++ {
-		char := line[i]
-
-		if !inQuotes {
-			if char == '"' || char == '\'' {
-				inQuotes = true
-				quoteChar = char
-			} else if char == '#' {
-				// Found unquoted comment, trim everything after
-				return strings.TrimSpace(line[:i])
-			}
-		} else {
-			if char == quoteChar {
-				// Check if it's escaped
-				if i > 0 && line[i-1] != '\\' {
-					inQuotes = false
-					quoteChar = 0
-				}
+		if p.strict {
+			if first, exists := seen[stmt.key]; exists {
+				return nil, fmt.Errorf("parse error on line %d: duplicate key %s (first defined on line %d)", stmt.line, stmt.key, first)
 			}
+			seen[stmt.key] = stmt.line
 		}
+		result[stmt.key] = stmt.value
 	}
 
-	return line
+	return result, nil
 }
 
-// unescapeDoubleQuoted processes escape sequences in double-quoted strings
-func (p *Parser) unescapeDoubleQuoted(value string) string {
-	result := strings.Builder{}
-
-	for i := 0; i < len(value); i++ {
-		if value[i] == '\\' && i+1 < len(value) {
-			next := value[i+1]
-			switch next {
-			case 'n':
-				result.WriteByte('\n')
-			case 'r':
-				result.WriteByte('\r')
-			case 't':
-				result.WriteByte('\t')
-			case '\\':
-				result.WriteByte('\\')
-			case '"':
-				result.WriteByte('"')
-			case '\'':
-				result.WriteByte('\'')
-			default:
-				// Unknown escape, keep the backslash
-				result.WriteByte('\\')
-				result.WriteByte(next)
-			}
-			i++ // Skip the next character
-		} else {
-			result.WriteByte(value[i])
-		}
+// readAll reads the whole input, sizing the buffer up front when the reader
+// knows its length (strings.Reader, bytes.Reader, bytes.Buffer)
+func readAll(reader io.Reader) ([]byte, error) {
+	size := bytes.MinRead
+	if sized, ok := reader.(interface{ Len() int }); ok {
+		size += sized.Len()
 	}
 
-	return result.String()
+	buf := bytes.NewBuffer(make([]byte, 0, size))
+	_, err := buf.ReadFrom(reader)
+	return buf.Bytes(), err
 }
 
-// expandVariables expands variable references in the format $VAR or ${VAR}
-func (p *Parser) expandVariables(value string, env map[string]string) string {
-	return expandVarRegex.ReplaceAllStringFunc(value, func(match string) string {
-		var varName string
-
-		if strings.HasPrefix(match, "${") && strings.HasSuffix(match, "}") {
-			// ${VAR} format
-			varName = match[2 : len(match)-1]
-		} else if strings.HasPrefix(match, "$") {
-			// $VAR format
-			varName = match[1:]
-		}
-
-		// Look up in parsed env first, then in OS env
-		if val, exists := env[varName]; exists {
-			return val
-		}
+// lookup resolves a variable reference against the parsed values first and
+// the OS environment second
+func (p *Parser) lookup(name []byte) string {
+	if val, exists := p.env[string(name)]; exists {
+		return val
+	}
 
-		if val, exists := os.LookupEnv(varName); exists {
-			return val
-		}
+	if val, exists := os.LookupEnv(string(name)); exists {
+		return val
+	}
 
-		// Variable not found, return empty string (bash behavior)
-		return ""
-	})
+	// Variable not found, return empty string (bash behavior)
+	return ""
 }
 
 // ParseInt parses an environment variable as an integer
@@ -0,0 +1,205 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ParseProperties reads a Java .properties file. Keys are normalized into
+// variable names as by ParseJSON: characters that are not valid in variable
+// names become underscores, a leading digit is prefixed with one and letters
+// are upper-cased, so db.url becomes DB_URL. Comment lines start with # or
+// !, keys are separated from values by =, : or whitespace, a trailing
+// backslash continues the value on the next line and \uXXXX escapes are
+// decoded. When two keys normalize to the same name, the later one wins.
+func ParseProperties(reader io.Reader) (map[string]string, error) {
+	src, err := readAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading input: %w", err)
+	}
+
+	result := make(map[string]string)
+	lines := strings.Split(string(src), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimLeft(strings.TrimSuffix(lines[i], "\r"), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		// Join continuation lines, dropping their leading whitespace
+		for continuesLine(line) && i+1 < len(lines) {
+			i++
+			next := strings.TrimLeft(strings.TrimSuffix(lines[i], "\r"), " \t\f")
+			line = line[:len(line)-1] + next
+		}
+		if continuesLine(line) {
+			line = line[:len(line)-1]
+		}
+
+		key, value, err := splitProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("parse error on line %d: %w", lineNumber, err)
+		}
+		result[normalizeKey(key)] = value
+	}
+
+	return result, nil
+}
+
+// continuesLine reports whether line ends with an odd number of backslashes
+func continuesLine(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// splitProperty splits a logical line into its unescaped key and value
+func splitProperty(line string) (string, string, error) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' {
+			i++
+			continue
+		}
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			end = i
+			break
+		}
+	}
+
+	key, err := unescapeProperty(line[:end])
+	if err != nil {
+		return "", "", err
+	}
+
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	value, err := unescapeProperty(rest)
+	return key, value, err
+}
+
+// unescapeProperty decodes the escape sequences of a .properties key or
+// value, combining \uXXXX surrogate pairs. A surrogate that is not part of
+// a pair becomes U+FFFD, as Java does when decoding it.
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	var high rune // pending high surrogate
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i+1 >= len(s) {
+			if high != 0 {
+				b.WriteRune(utf8.RuneError)
+				high = 0
+			}
+			b.WriteByte(c)
+			continue
+		}
+
+		i++
+		var r rune
+		switch s[i] {
+		case 't':
+			r = '\t'
+		case 'n':
+			r = '\n'
+		case 'r':
+			r = '\r'
+		case 'f':
+			r = '\f'
+		case 'u':
+			v, ok := parseHex([]byte(s[i+1:]), 4)
+			if !ok {
+				return "", fmt.Errorf("malformed \\u escape")
+			}
+			i += 4
+			r = rune(v)
+		default:
+			r = rune(s[i])
+		}
+
+		if high != 0 {
+			// DecodeRune returns U+FFFD unless r completes the pair
+			pair := utf16.DecodeRune(high, r)
+			high = 0
+			if pair != utf8.RuneError {
+				b.WriteRune(pair)
+				continue
+			}
+			b.WriteRune(utf8.RuneError)
+		}
+		if r >= 0xD800 && r < 0xDC00 {
+			high = r
+			continue
+		}
+		// WriteRune writes a lone low surrogate as U+FFFD
+		b.WriteRune(r)
+	}
+	if high != 0 {
+		b.WriteRune(utf8.RuneError)
+	}
+
+	return b.String(), nil
+}
+
+// escapeProperty escapes a key or value for a .properties file. Non-ASCII
+// characters are written as \uXXXX so the output is valid in the ISO-8859-1
+// encoding older Java versions expect.
+func escapeProperty(s string, isKey bool) string {
+	var b strings.Builder
+
+	for i, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':':
+			if isKey {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		case ' ':
+			if isKey || i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		case '#', '!':
+			if isKey && i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		default:
+			if r < 0x20 || r > 0x7e {
+				for _, u := range utf16.Encode([]rune{r}) {
+					fmt.Fprintf(&b, `\u%04X`, u)
+				}
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+
+	return b.String()
+}
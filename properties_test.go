@@ -0,0 +1,135 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseProperties(t *testing.T) {
+	input := "# comment\n" +
+		"! also a comment\n" +
+		"db.url=jdbc:postgresql://localhost/app\n" +
+		"db.user : admin\n" +
+		"greeting Hello World\n" +
+		"multi = first, \\\n" +
+		"        second, \\\n" +
+		"        third\n" +
+		"unicode=caf\\u00e9 \\uD83D\\uDE00\n" +
+		"key\\ with\\ spaces=value\n" +
+		"escaped\\=key=a\\tb\n" +
+		"empty=\n" +
+		"windows=line\r\n" +
+		"9lives=cat\n"
+
+	env, err := ParseProperties(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseProperties failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"DB_URL":          "jdbc:postgresql://localhost/app",
+		"DB_USER":         "admin",
+		"GREETING":        "Hello World",
+		"MULTI":           "first, second, third",
+		"UNICODE":         "café 😀",
+		"KEY_WITH_SPACES": "value",
+		"ESCAPED_KEY":     "a\tb",
+		"EMPTY":           "",
+		"WINDOWS":         "line",
+		"_9LIVES":         "cat",
+	}
+
+	if len(env) != len(expected) {
+		t.Errorf("Expected %d keys, got %d: %v", len(expected), len(env), env)
+	}
+	for key, value := range expected {
+		if env[key] != value {
+			t.Errorf("Expected %q=%q, got %q", key, value, env[key])
+		}
+	}
+
+	if _, err := ParseProperties(strings.NewReader("bad=\\u12")); err == nil {
+		t.Error("Expected error for malformed unicode escape")
+	}
+}
+
+func TestPropertiesLoneSurrogates(t *testing.T) {
+	tests := map[string]string{
+		`\uD83D\uDE00`:       "😀",
+		`a\uD800`:            "a\uFFFD",
+		`\uD800b`:            "\uFFFDb",
+		`\uDC00`:             "\uFFFD",
+		`\uD800\uD83D\uDE00`: "\uFFFD😀",
+		`\uD800\n`:           "\uFFFD\n",
+		`\uDE00\uD83D`:       "\uFFFD\uFFFD",
+	}
+
+	for input, want := range tests {
+		env, err := ParseProperties(strings.NewReader("key=" + input))
+		if err != nil {
+			t.Errorf("%s: %v", input, err)
+			continue
+		}
+		if env["KEY"] != want {
+			t.Errorf("%s: expected %q, got %q", input, want, env["KEY"])
+		}
+	}
+}
+
+func TestPropertiesRoundTrip(t *testing.T) {
+	env := map[string]string{
+		"DB_URL":      "jdbc:mysql://host:3306/db?a=b",
+		"COLON":       "key:value",
+		"HASH":        "#!bang",
+		"SPACED":      "  leading spaces",
+		"MULTILINE":   "one\ntwo",
+		"UNICODE":     "naïve 日本 😀",
+		"BACKSLASHES": `C:\path\to`,
+	}
+
+	content, err := MarshalFormat(env, FormatProperties)
+	if err != nil {
+		t.Fatalf("MarshalFormat failed: %v", err)
+	}
+
+	parsed, err := ParseProperties(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseProperties failed: %v\n%s", err, content)
+	}
+
+	for key, value := range env {
+		if parsed[key] != value {
+			t.Errorf("Round trip of %q: expected %q, got %q", key, value, parsed[key])
+		}
+	}
+
+	// Keys are normalized when parsed, so check their escaping directly
+	for _, key := range []string{"db.url", "key:colon", "#hash", "spaced key", "eq=key", "naïve"} {
+		got, value, err := splitProperty(escapeProperty(key, true) + "=" + escapeProperty("v", false))
+		if err != nil || got != key || value != "v" {
+			t.Errorf("Round trip of key %q: got %q=%q, %v", key, got, value, err)
+		}
+	}
+}
+
+func TestReadPropertiesAndINIFiles(t *testing.T) {
+	dir := t.TempDir()
+	props := filepath.Join(dir, "app.properties")
+	ini := filepath.Join(dir, "app.ini")
+	if err := os.WriteFile(props, []byte("app.name=demo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ini, []byte("[server]\nport = 8080\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := Read(props, ini)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if env["APP_NAME"] != "demo" || env["SERVER_PORT"] != "8080" {
+		t.Errorf("Unexpected result: %v", env)
+	}
+}
@@ -0,0 +1,123 @@
+package dotenv
+
+import (
+	"log/slog"
+	"path"
+	"strings"
+)
+
+// Redacted is printed in place of secret values
+const Redacted = "[redacted]"
+
+// DefaultSecretPatterns are the key patterns treated as secret when a
+// RedactPolicy has none. They are matched with path.Match against the upper
+// case key.
+var DefaultSecretPatterns = []string{
+	"*SECRET*",
+	"*PASSWORD*",
+	"*PASSWD*",
+	"*TOKEN*",
+	"*API_KEY*",
+	"*APIKEY*",
+	"*ACCESS_KEY*",
+	"*PRIVATE_KEY*",
+	"*CREDENTIAL*",
+}
+
+// Secret is a value that prints as Redacted, so it can be passed to fmt,
+// log/slog or encoders without leaking; convert it to string to use it
+type Secret string
+
+// String returns Redacted
+func (s Secret) String() string {
+	return Redacted
+}
+
+// GoString returns Redacted, so %#v does not reveal the value either
+func (s Secret) GoString() string {
+	return Redacted
+}
+
+// LogValue implements slog.LogValuer
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// MarshalText returns Redacted, which also covers JSON
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// RedactPolicy decides which keys hold secrets whose values must be kept out
+// of error messages, reports and logs. The zero value uses
+// DefaultSecretPatterns.
+type RedactPolicy struct {
+	// Patterns are path.Match patterns for upper case keys, such as
+	// "*_TOKEN"; nil means DefaultSecretPatterns
+	Patterns []string
+	// Keys are secret regardless of the patterns, such as the keys marked
+	// @secret in a schema
+	Keys []string
+}
+
+// IsSecret reports whether the value of key is secret
+func (p RedactPolicy) IsSecret(key string) bool {
+	for _, k := range p.Keys {
+		if k == key {
+			return true
+		}
+	}
+
+	patterns := p.Patterns
+	if patterns == nil {
+		patterns = DefaultSecretPatterns
+	}
+	upper := strings.ToUpper(key)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToUpper(pattern), upper); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns value, or Redacted when key is secret and value is not
+// empty
+func (p RedactPolicy) Redact(key, value string) string {
+	if value != "" && p.IsSecret(key) {
+		return Redacted
+	}
+	return value
+}
+
+// RedactMap returns a copy of env with secret values replaced by Redacted
+func (p RedactPolicy) RedactMap(env map[string]string) map[string]string {
+	result := make(map[string]string, len(env))
+	for key, value := range env {
+		result[key] = p.Redact(key, value)
+	}
+	return result
+}
+
+// IsSecretKey reports whether key matches DefaultSecretPatterns
+func IsSecretKey(key string) bool {
+	return RedactPolicy{}.IsSecret(key)
+}
+
+// RedactPolicy returns a policy treating the keys marked @secret as secret
+// in addition to those matching DefaultSecretPatterns
+func (s *Schema) RedactPolicy() RedactPolicy {
+	var p RedactPolicy
+	for _, f := range s.Fields {
+		if f.Secret {
+			p.Keys = append(p.Keys, f.Key)
+		}
+	}
+	return p
+}
+
+// IsSecret reports whether the field is marked @secret or its key matches
+// DefaultSecretPatterns
+func (f *Field) IsSecret() bool {
+	return f.Secret || IsSecretKey(f.Key)
+}
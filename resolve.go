@@ -0,0 +1,193 @@
+package dotenv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resolver looks up the value a reference such as secret://prod/db#password
+// stands for. The reference is passed exactly as written in the .env file.
+type Resolver interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// ResolverFunc adapts a function to the Resolver interface
+type ResolverFunc func(ctx context.Context, ref string) (string, error)
+
+// Resolve calls f(ctx, ref)
+func (f ResolverFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+// DefaultResolveTimeout bounds each lookup when ResolveOptions.Timeout is 0
+const DefaultResolveTimeout = 10 * time.Second
+
+// resolvers holds the registered resolvers by scheme
+var (
+	resolversMu sync.RWMutex
+	resolvers   = map[string]Resolver{
+		"file": ResolverFunc(resolveFile),
+		"exec": ResolverFunc(resolveExec),
+	}
+)
+
+// RegisterResolver makes r handle values starting with scheme://, replacing
+// any resolver registered for the scheme before. Passing a nil resolver
+// removes the scheme. The file and exec schemes are registered by default.
+func RegisterResolver(scheme string, r Resolver) {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+
+	scheme = strings.ToLower(scheme)
+	if r == nil {
+		delete(resolvers, scheme)
+		return
+	}
+	resolvers[scheme] = r
+}
+
+// ResolveOptions configures Resolve
+type ResolveOptions struct {
+	// Timeout bounds each lookup, DefaultResolveTimeout when 0
+	Timeout time.Duration
+}
+
+// Resolve returns a copy of env in which every value that is a reference to
+// a registered scheme, such as file:///run/secrets/db, is replaced by what
+// its resolver returns. Values with other schemes are left untouched, and a
+// reference used by several keys is only looked up once.
+//
+// Resolving is never done implicitly by Read or Load because references can
+// run commands (exec://); call Resolve on their result instead.
+func Resolve(ctx context.Context, env map[string]string, opts ResolveOptions) (map[string]string, error) {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultResolveTimeout
+	}
+
+	// Resolve in key order so errors are deterministic
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]string, len(env))
+	cache := make(map[string]string)
+
+	for _, key := range keys {
+		value := env[key]
+		r := lookupResolver(value)
+		if r == nil {
+			result[key] = value
+			continue
+		}
+
+		resolved, ok := cache[value]
+		if !ok {
+			var err error
+			resolved, err = resolveWithTimeout(ctx, r, value, timeout)
+			if err != nil {
+				return nil, fmt.Errorf("resolving %s: %w", key, err)
+			}
+			cache[value] = resolved
+		}
+		result[key] = resolved
+	}
+
+	return result, nil
+}
+
+// lookupResolver returns the resolver for the scheme of value, or nil
+func lookupResolver(value string) Resolver {
+	scheme, _, ok := strings.Cut(value, "://")
+	if !ok || scheme == "" {
+		return nil
+	}
+
+	resolversMu.RLock()
+	defer resolversMu.RUnlock()
+	return resolvers[strings.ToLower(scheme)]
+}
+
+// resolveWithTimeout calls r, giving up once timeout has passed even if the
+// resolver ignores its context
+func resolveWithTimeout(ctx context.Context, r Resolver, ref string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		value string
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := r.Resolve(ctx, ref)
+		done <- result{value, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.value, res.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("timed out after %v", timeout)
+		}
+		return "", ctx.Err()
+	}
+}
+
+// resolveFile reads the file named by a file:// reference. Both
+// file:///absolute/path and file://relative/path are accepted; one trailing
+// newline is removed.
+func resolveFile(ctx context.Context, ref string) (string, error) {
+	path := ref[len("file://"):]
+	if rest, ok := strings.CutPrefix(path, "localhost/"); ok {
+		path = "/" + rest
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return trimNewline(string(data)), nil
+}
+
+// resolveExec runs the command of an exec:// reference, split on spaces
+// without a shell (exec://pass show prod/db), and returns its output
+// without the trailing newline
+func resolveExec(ctx context.Context, ref string) (string, error) {
+	args := strings.Fields(ref[len("exec://"):])
+	if len(args) == 0 {
+		return "", errors.New("empty exec:// command")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", args[0], err)
+	}
+	return trimNewline(stdout.String()), nil
+}
+
+// trimNewline removes one trailing \n or \r\n
+func trimNewline(s string) string {
+	if rest, ok := strings.CutSuffix(s, "\r\n"); ok {
+		return rest
+	}
+	return strings.TrimSuffix(s, "\n")
+}
@@ -0,0 +1,112 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "db_password")
+	if err := os.WriteFile(secretFile, []byte("hunter2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A stand-in for an external secret manager
+	var calls atomic.Int32
+	RegisterResolver("secret", ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		calls.Add(1)
+		path, field, _ := strings.Cut(strings.TrimPrefix(ref, "secret://"), "#")
+		if path != "prod/db" {
+			return "", errors.New("not found")
+		}
+		return field + "-value", nil
+	}))
+	defer RegisterResolver("secret", nil)
+
+	env := map[string]string{
+		"DB_PASSWORD": "file://" + secretFile,
+		"DB_USER":     "secret://prod/db#user",
+		"DB_USER_TOO": "secret://prod/db#user",
+		"DB_PASS":     "secret://prod/db#password",
+		"HOMEPAGE":    "https://example.com",
+		"PLAIN":       "value",
+	}
+
+	resolved, err := Resolve(context.Background(), env, ResolveOptions{})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"DB_PASSWORD": "hunter2",
+		"DB_USER":     "user-value",
+		"DB_USER_TOO": "user-value",
+		"DB_PASS":     "password-value",
+		"HOMEPAGE":    "https://example.com",
+		"PLAIN":       "value",
+	}
+	for key, value := range expected {
+		if resolved[key] != value {
+			t.Errorf("Expected %s=%q, got %q", key, value, resolved[key])
+		}
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Expected 2 lookups thanks to caching, got %d", n)
+	}
+	if env["DB_USER"] != "secret://prod/db#user" {
+		t.Error("Resolve modified its input")
+	}
+
+	_, err = Resolve(context.Background(), map[string]string{"X": "secret://other"}, ResolveOptions{})
+	if err == nil || !strings.Contains(err.Error(), "resolving X: not found") {
+		t.Errorf("Expected resolver error, got %v", err)
+	}
+
+	_, err = Resolve(context.Background(), map[string]string{"X": "file://" + filepath.Join(dir, "missing")}, ResolveOptions{})
+	if err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestResolveTimeout(t *testing.T) {
+	RegisterResolver("slow", ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		time.Sleep(time.Second) // Ignores ctx on purpose
+		return "late", nil
+	}))
+	defer RegisterResolver("slow", nil)
+
+	start := time.Now()
+	_, err := Resolve(context.Background(), map[string]string{"X": "slow://x"}, ResolveOptions{Timeout: 20 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected timeout, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Resolve waited for a resolver that ignored its context")
+	}
+}
+
+func TestResolveExec(t *testing.T) {
+	if _, err := exec.LookPath("echo"); err != nil {
+		t.Skip("echo not available")
+	}
+
+	resolved, err := Resolve(context.Background(), map[string]string{"TOKEN": "exec://echo from-command"}, ResolveOptions{})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if resolved["TOKEN"] != "from-command" {
+		t.Errorf("Expected from-command, got %q", resolved["TOKEN"])
+	}
+
+	if _, err := Resolve(context.Background(), map[string]string{"X": "exec://"}, ResolveOptions{}); err == nil {
+		t.Error("Expected error for empty command")
+	}
+}
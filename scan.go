@@ -0,0 +1,322 @@
+package dotenv
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Names of the checks made by Scan
+const (
+	// ScanAWSAccessKey reports AWS access key IDs
+	ScanAWSAccessKey = "aws-access-key"
+	// ScanGitHubToken reports GitHub personal access, OAuth, app and
+	// refresh tokens
+	ScanGitHubToken = "github-token"
+	// ScanPrivateKey reports PEM private key blocks
+	ScanPrivateKey = "private-key"
+	// ScanJWT reports JSON Web Tokens
+	ScanJWT = "jwt"
+	// ScanHighEntropy reports random-looking strings
+	ScanHighEntropy = "high-entropy"
+)
+
+// Defaults for ScanOptions
+const (
+	DefaultScanEntropy   = 4.0
+	DefaultScanMinLength = 20
+)
+
+// DefaultAllowlistFile is the allowlist the CLI reads when none is given
+const DefaultAllowlistFile = ".dotenv-allowlist"
+
+// ScanOptions configures Scan and ScanFile
+type ScanOptions struct {
+	// Entropy is the Shannon entropy, in bits per character, from which a
+	// string is reported as random; DefaultScanEntropy when zero. Hex
+	// strings, which carry at most 4 bits per character, are held to three
+	// quarters of it.
+	Entropy float64
+	// MinLength is the length from which strings are checked for entropy;
+	// DefaultScanMinLength when zero
+	MinLength int
+	// Allow suppresses the findings it matches; may be nil
+	Allow *Allowlist
+}
+
+// SecretFinding is a likely credential found by Scan. It never holds the
+// value itself.
+type SecretFinding struct {
+	// File is left empty by Scan; ScanFile fills it in
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line"`
+	Key     string `json:"key"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	// Fingerprint identifies the value, for use in an allowlist
+	Fingerprint string `json:"fingerprint"`
+}
+
+func (f SecretFinding) String() string {
+	if f.File == "" {
+		return fmt.Sprintf("line %d: %s (%s)", f.Line, f.Message, f.Rule)
+	}
+	return fmt.Sprintf("%s:%d: %s (%s)", f.File, f.Line, f.Message, f.Rule)
+}
+
+// scanPatterns are the known credential formats, checked in order before
+// entropy
+var scanPatterns = []struct {
+	rule    string
+	pattern *regexp.Regexp
+	message string
+}{
+	{ScanPrivateKey, regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----`), "%s contains a private key"},
+	{ScanAWSAccessKey, regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`), "%s looks like an AWS access key ID"},
+	{ScanGitHubToken, regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`), "%s looks like a GitHub token"},
+	{ScanJWT, regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]*`), "%s looks like a JSON Web Token"},
+}
+
+// Scan reports values in doc that look like real credentials: known
+// formats such as AWS access keys, GitHub tokens, private keys and JWTs,
+// and high-entropy strings. Values are checked as written, without
+// expansion; encrypted values are skipped.
+func Scan(doc *Document, opts ScanOptions) []SecretFinding {
+	return scanDocument(doc, "", opts)
+}
+
+// ScanFile reads filename and scans it; see Scan
+func ScanFile(filename string, opts ScanOptions) ([]SecretFinding, error) {
+	doc, err := ReadDocument(filename)
+	if err != nil {
+		return nil, err
+	}
+	return scanDocument(doc, filename, opts), nil
+}
+
+// scanDocument scans doc, naming file in findings and allowlist matches
+func scanDocument(doc *Document, file string, opts ScanOptions) []SecretFinding {
+	var findings []SecretFinding
+	for _, n := range doc.nodes {
+		e := n.entry
+		if e == nil || e.value == "" || IsEncrypted(e.value) {
+			continue
+		}
+
+		rule, message, ok := scanValue(e.value, opts)
+		if !ok {
+			continue
+		}
+		f := SecretFinding{
+			File:        file,
+			Line:        n.line,
+			Key:         e.key,
+			Rule:        rule,
+			Message:     fmt.Sprintf(message, e.key),
+			Fingerprint: Fingerprint(e.value),
+		}
+		if !opts.Allow.Allows(f) {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// scanValue returns the rule matched by value and its message format
+func scanValue(value string, opts ScanOptions) (string, string, bool) {
+	for _, p := range scanPatterns {
+		if p.pattern.MatchString(value) {
+			return p.rule, p.message, true
+		}
+	}
+
+	threshold := opts.Entropy
+	if threshold == 0 {
+		threshold = DefaultScanEntropy
+	}
+	minLength := opts.MinLength
+	if minLength == 0 {
+		minLength = DefaultScanMinLength
+	}
+
+	words := strings.FieldsFunc(value, func(r rune) bool {
+		return strings.ContainsRune(" \t\r\n\"'`,;", r)
+	})
+	for _, word := range words {
+		// URLs and variable references are long without being random
+		if len(word) < minLength || strings.Contains(word, "://") || strings.Contains(word, "$") ||
+			!strings.ContainsAny(word, "0123456789") || !containsLetter(word) {
+			continue
+		}
+
+		limit := threshold
+		if isHex(word) {
+			limit = threshold * 3 / 4
+		}
+		if entropy := shannonEntropy(word); entropy >= limit {
+			return ScanHighEntropy, fmt.Sprintf("%%s looks like a secret: %.1f bits of entropy per character", entropy), true
+		}
+	}
+	return "", "", false
+}
+
+// shannonEntropy returns the entropy of s in bits per byte
+func shannonEntropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+
+	entropy := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(len(s))
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// isHex reports whether s consists of hexadecimal digits only
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// containsLetter reports whether s contains an ASCII letter
+func containsLetter(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; c >= 'a' && c <= 'z' {
+			return true
+		}
+	}
+	return false
+}
+
+// Fingerprint identifies a value without revealing it, as "sha256:" and the
+// hex digest
+func Fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Allowlist holds accepted Scan findings. Each line of an allowlist file is
+// one of
+//
+//	KEY          a key, or a path.Match pattern of keys, in any file
+//	FILE:KEY     a key in files matching FILE, by path or base name
+//	sha256:HEX   a value with the given Fingerprint, wherever it appears
+//
+// Blank lines and lines starting with # are ignored, as is text after " #".
+type Allowlist struct {
+	entries []allowEntry
+}
+
+// allowEntry is one line of an allowlist
+type allowEntry struct {
+	file, key, fingerprint string
+}
+
+// ParseAllowlist parses an allowlist; see Allowlist
+func ParseAllowlist(reader io.Reader) (*Allowlist, error) {
+	a := &Allowlist{}
+	scanner := bufio.NewScanner(reader)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, " #"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		var e allowEntry
+		if strings.HasPrefix(text, "sha256:") {
+			e.fingerprint = text
+		} else {
+			e.key = text
+			if i := strings.LastIndexByte(text, ':'); i >= 0 {
+				e.file, e.key = text[:i], text[i+1:]
+			}
+			if _, err := path.Match(e.key, ""); err != nil || e.key == "" {
+				return nil, fmt.Errorf("line %d: invalid key pattern %q", line, e.key)
+			}
+			if _, err := path.Match(e.file, ""); err != nil {
+				return nil, fmt.Errorf("line %d: invalid file pattern %q", line, e.file)
+			}
+		}
+		a.entries = append(a.entries, e)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading input: %w", err)
+	}
+	return a, nil
+}
+
+// ReadAllowlist reads an allowlist file; see Allowlist
+func ReadAllowlist(filename string) (*Allowlist, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open allowlist %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	a, err := ParseAllowlist(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return a, nil
+}
+
+// Allows reports whether the allowlist accepts f. A nil Allowlist accepts
+// nothing.
+func (a *Allowlist) Allows(f SecretFinding) bool {
+	if a == nil {
+		return false
+	}
+
+	for _, e := range a.entries {
+		if e.fingerprint != "" {
+			if e.fingerprint == f.Fingerprint {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(e.key, f.Key); !ok {
+			continue
+		}
+		if e.file == "" || matchFile(e.file, f.File) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFile reports whether pattern matches file by path or base name
+func matchFile(pattern, file string) bool {
+	if file == "" {
+		return false
+	}
+	file = filepath.ToSlash(filepath.Clean(file))
+	if ok, _ := path.Match(pattern, file); ok {
+		return true
+	}
+	ok, _ := path.Match(pattern, path.Base(file))
+	return ok
+}
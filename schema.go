@@ -0,0 +1,430 @@
+package dotenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is the type of a variable described by a Schema
+type Type string
+
+// Types understood by Schema
+const (
+	TypeString   Type = "string"
+	TypeInt      Type = "int"
+	TypeFloat    Type = "float"
+	TypeBool     Type = "bool"
+	TypeURL      Type = "url"
+	TypeDuration Type = "duration"
+)
+
+// Field describes one variable of a Schema
+type Field struct {
+	Key         string   `json:"-"`
+	Description string   `json:"description,omitempty"`
+	Type        Type     `json:"type,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Example     string   `json:"example,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
+	// Min and Max bound numbers, or the length of strings
+	Min    *float64 `json:"min,omitempty"`
+	Max    *float64 `json:"max,omitempty"`
+	Secret bool     `json:"secret,omitempty"`
+	// Line is the line of the example file defining the key, 0 for JSON
+	Line int `json:"-"`
+
+	pattern *regexp.Regexp
+	// typed records that the type was given explicitly
+	typed bool
+}
+
+// Schema describes the variables an application expects
+type Schema struct {
+	// Fields are in the order of the schema file
+	Fields []Field
+}
+
+// Field returns the description of key
+func (s *Schema) Field(key string) (Field, bool) {
+	for _, f := range s.Fields {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// ParseSchema reads a schema from an annotated .env.example. Comment lines
+// directly above a key describe it: lines starting with @ hold annotations,
+// other lines form the description, and the example value is the default.
+//
+//	# Port the HTTP server listens on
+//	# @type int @required @min 1 @max 65535
+//	PORT=8080
+//
+// The annotations are @type (string, int, float, bool, url or duration),
+// @required, @min, @max, @enum a,b,c, @pattern REGEXP, @secret, @example
+// VALUE and @default VALUE, which overrides the example value.
+func ParseSchema(reader io.Reader) (*Schema, error) {
+	doc, err := ParseDocument(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{}
+	var comments []*node
+
+	for _, n := range doc.nodes {
+		if n.entry == nil {
+			text := strings.TrimSpace(n.raw)
+			if text == "" {
+				comments = nil // A blank line ends the block
+			} else {
+				comments = append(comments, n)
+			}
+			continue
+		}
+
+		field := Field{Key: n.entry.key, Type: TypeString, Default: n.entry.value, Line: n.line}
+		var description []string
+		for _, c := range comments {
+			text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(c.raw), "#"))
+			if !strings.HasPrefix(text, "@") {
+				if text != "" {
+					description = append(description, text)
+				}
+				continue
+			}
+			if err := field.annotate(text); err != nil {
+				return nil, fmt.Errorf("line %d: %w", c.line, err)
+			}
+		}
+		field.Description = strings.Join(description, " ")
+		comments = nil
+
+		if err := field.compile(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n.line, err)
+		}
+		schema.set(field)
+	}
+
+	return schema, nil
+}
+
+// ParseSchemaJSON reads a schema from a JSON object mapping each key to its
+// description, for example {"PORT": {"type": "int", "required": true}}.
+// Fields are sorted by key.
+func ParseSchemaJSON(reader io.Reader) (*Schema, error) {
+	var fields map[string]Field
+	if err := json.NewDecoder(reader).Decode(&fields); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	schema := &Schema{}
+	for _, key := range keys {
+		field := fields[key]
+		field.Key = key
+		field.typed = field.Type != ""
+		if !field.typed {
+			field.Type = TypeString
+		}
+		if err := field.compile(); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		schema.Fields = append(schema.Fields, field)
+	}
+	return schema, nil
+}
+
+// ReadSchema reads a schema file, using ParseSchemaJSON for files ending in
+// .json and ParseSchema otherwise
+func ReadSchema(filename string) (*Schema, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	defer f.Close()
+
+	var schema *Schema
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		schema, err = ParseSchemaJSON(f)
+	} else {
+		schema, err = ParseSchema(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return schema, nil
+}
+
+// set adds field, replacing an earlier definition of the same key
+func (s *Schema) set(field Field) {
+	for i, f := range s.Fields {
+		if f.Key == field.Key {
+			s.Fields[i] = field
+			return
+		}
+	}
+	s.Fields = append(s.Fields, field)
+}
+
+// annotations maps annotation names to whether they take free text, which
+// may itself contain words starting with @
+var annotations = map[string]bool{
+	"type": false, "required": false, "secret": false, "min": false, "max": false,
+	"enum": false, "pattern": true, "example": true, "default": true,
+}
+
+// annotate applies the @annotations in text to f
+func (f *Field) annotate(text string) error {
+	var name string
+	var args []string
+
+	apply := func() error {
+		if name == "" {
+			return nil
+		}
+		if err := f.setAnnotation(name, strings.Join(args, " ")); err != nil {
+			return fmt.Errorf("@%s: %w", name, err)
+		}
+		return nil
+	}
+
+	for _, word := range strings.Fields(text) {
+		if next, ok := strings.CutPrefix(word, "@"); ok {
+			if _, known := annotations[next]; known || !annotations[name] {
+				if !known {
+					return fmt.Errorf("unknown annotation %s", word)
+				}
+				if err := apply(); err != nil {
+					return err
+				}
+				name, args = next, nil
+				continue
+			}
+		}
+		args = append(args, word)
+	}
+	return apply()
+}
+
+// setAnnotation applies a single annotation to f
+func (f *Field) setAnnotation(name, arg string) error {
+	var err error
+	switch name {
+	case "type":
+		f.Type = Type(strings.ToLower(arg))
+		f.typed = true
+	case "required":
+		f.Required = true
+	case "secret":
+		f.Secret = true
+	case "min":
+		f.Min, err = parseBound(arg)
+	case "max":
+		f.Max, err = parseBound(arg)
+	case "enum":
+		f.Enum = nil
+		for _, v := range strings.Split(arg, ",") {
+			f.Enum = append(f.Enum, strings.TrimSpace(v))
+		}
+	case "pattern":
+		f.Pattern = arg
+	case "example":
+		f.Example = arg
+	case "default":
+		f.Default = arg
+	}
+	return err
+}
+
+// parseBound parses the argument of @min or @max
+func parseBound(arg string) (*float64, error) {
+	v, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", arg)
+	}
+	return &v, nil
+}
+
+// compile checks the field's type and compiles its pattern
+func (f *Field) compile() error {
+	switch f.Type {
+	case TypeString, TypeInt, TypeFloat, TypeBool, TypeURL, TypeDuration:
+	default:
+		return fmt.Errorf("unknown type %q", f.Type)
+	}
+
+	if f.Pattern != "" {
+		re, err := regexp.Compile("^(?:" + f.Pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		f.pattern = re
+	}
+	return nil
+}
+
+// Kinds of Problem reported by Validate
+const (
+	ProblemMissing = "missing"
+	ProblemUnknown = "unknown"
+	ProblemType    = "type"
+	ProblemRange   = "range"
+	ProblemEnum    = "enum"
+	ProblemPattern = "pattern"
+)
+
+// Problem is a single validation failure
+type Problem struct {
+	Key     string `json:"key"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return p.Message
+}
+
+// ValidationError is returned by Validate and lists every problem found
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		messages[i] = p.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Validate checks variables, such as the result of Read, against the
+// schema. It reports missing required keys, keys the schema does not
+// describe, values of the wrong type, out-of-range numbers, values outside
+// an @enum and values not matching a @pattern. The returned error is a
+// *ValidationError.
+func (s *Schema) Validate(env map[string]string) error {
+	var problems []Problem
+	known := make(map[string]bool)
+
+	for _, f := range s.Fields {
+		known[f.Key] = true
+		value, ok := env[f.Key]
+		if !ok || value == "" {
+			if f.Required {
+				problems = append(problems, Problem{f.Key, ProblemMissing, fmt.Sprintf("%s is required", f.Key)})
+			}
+			continue
+		}
+		if p, ok := f.check(value); !ok {
+			problems = append(problems, p)
+		}
+	}
+
+	unknown := make([]string, 0)
+	for key := range env {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		problems = append(problems, Problem{key, ProblemUnknown, fmt.Sprintf("%s is not in the schema", key)})
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// check validates a non-empty value against the field
+func (f *Field) check(value string) (Problem, bool) {
+	problem := func(kind, format string, args ...any) (Problem, bool) {
+		return Problem{f.Key, kind, f.Key + " " + fmt.Sprintf(format, args...)}, false
+	}
+
+	// Type errors quote the value, unless it is a secret
+	var shown any = value
+	if f.IsSecret() {
+		shown = Secret(value)
+	}
+
+	number := float64(len([]rune(value)))
+	switch f.Type {
+	case TypeInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return problem(ProblemType, "must be an integer, got %q", shown)
+		}
+		number = float64(n)
+	case TypeFloat:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(n) {
+			return problem(ProblemType, "must be a number, got %q", shown)
+		}
+		number = n
+	case TypeBool:
+		switch strings.ToLower(value) {
+		case "true", "false", "1", "0", "yes", "no", "on", "off":
+		default:
+			return problem(ProblemType, "must be a boolean, got %q", shown)
+		}
+	case TypeURL:
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" && u.Opaque == "" {
+			return problem(ProblemType, "must be a URL, got %q", shown)
+		}
+	case TypeDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return problem(ProblemType, "must be a duration such as 30s, got %q", shown)
+		}
+	}
+
+	if f.Type == TypeInt || f.Type == TypeFloat || f.Type == TypeString {
+		unit := ""
+		if f.Type == TypeString {
+			unit = " characters"
+		}
+		if f.Min != nil && number < *f.Min {
+			return problem(ProblemRange, "must be at least %v%s", *f.Min, unit)
+		}
+		if f.Max != nil && number > *f.Max {
+			return problem(ProblemRange, "must be at most %v%s", *f.Max, unit)
+		}
+	}
+
+	if len(f.Enum) > 0 {
+		found := false
+		for _, v := range f.Enum {
+			found = found || v == value
+		}
+		if !found {
+			return problem(ProblemEnum, "must be one of %s", strings.Join(f.Enum, ", "))
+		}
+	}
+
+	if f.pattern != nil && !f.pattern.MatchString(value) {
+		return problem(ProblemPattern, "must match %s", f.Pattern)
+	}
+
+	return Problem{}, true
+}
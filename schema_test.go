@@ -0,0 +1,156 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const schemaExample = `# Port the HTTP server listens on
+# @type int @required @min 1 @max 65535
+PORT=8080
+
+# Deployment environment
+# @enum development,staging,production
+APP_ENV=development
+
+# @type url @required @secret
+DATABASE_URL=
+
+# Contact address
+# @pattern [^@]+@example\.com @example ops@example.com
+CONTACT=
+
+# @type duration
+TIMEOUT=30s
+
+# @type bool
+DEBUG=false
+
+# Unrelated comment, separated by a blank line
+
+NAME=app
+`
+
+func TestParseSchema(t *testing.T) {
+	schema, err := ParseSchema(strings.NewReader(schemaExample))
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+
+	var keys []string
+	for _, f := range schema.Fields {
+		keys = append(keys, f.Key)
+	}
+	expectedKeys := []string{"PORT", "APP_ENV", "DATABASE_URL", "CONTACT", "TIMEOUT", "DEBUG", "NAME"}
+	if !reflect.DeepEqual(keys, expectedKeys) {
+		t.Errorf("Expected keys %v, got %v", expectedKeys, keys)
+	}
+
+	port, _ := schema.Field("PORT")
+	if port.Type != TypeInt || !port.Required || port.Default != "8080" || *port.Min != 1 || *port.Max != 65535 ||
+		port.Description != "Port the HTTP server listens on" || port.Line != 3 {
+		t.Errorf("Unexpected PORT field: %+v", port)
+	}
+
+	db, _ := schema.Field("DATABASE_URL")
+	if db.Type != TypeURL || !db.Secret || db.Description != "" {
+		t.Errorf("Unexpected DATABASE_URL field: %+v", db)
+	}
+
+	contact, _ := schema.Field("CONTACT")
+	if contact.Pattern != `[^@]+@example\.com` || contact.Example != "ops@example.com" {
+		t.Errorf("Unexpected CONTACT field: %+v", contact)
+	}
+
+	name, _ := schema.Field("NAME")
+	if name.Description != "" || name.Type != TypeString {
+		t.Errorf("Unexpected NAME field: %+v", name)
+	}
+
+	for _, bad := range []string{"# @type integer\nA=1\n", "# @requried\nA=1\n", "# @min ten\nA=1\n", "# @pattern (\nA=1\n"} {
+		if _, err := ParseSchema(strings.NewReader(bad)); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	schema, err := ParseSchema(strings.NewReader(schemaExample))
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+
+	valid := map[string]string{
+		"PORT":         "443",
+		"APP_ENV":      "production",
+		"DATABASE_URL": "postgres://db.internal/app",
+		"CONTACT":      "ops@example.com",
+		"TIMEOUT":      "1m",
+		"DEBUG":        "yes",
+	}
+	if err := schema.Validate(valid); err != nil {
+		t.Errorf("Expected valid, got %v", err)
+	}
+
+	invalid := map[string]string{
+		"PORT":    "70000",
+		"APP_ENV": "test",
+		"CONTACT": "ops@example.org",
+		"TIMEOUT": "soon",
+		"DEBUG":   "maybe",
+		"EXTRA":   "1",
+	}
+	err = schema.Validate(invalid)
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+
+	kinds := make(map[string]string)
+	for _, p := range verr.Problems {
+		kinds[p.Key] = p.Kind
+	}
+	expected := map[string]string{
+		"PORT":         ProblemRange,
+		"APP_ENV":      ProblemEnum,
+		"DATABASE_URL": ProblemMissing,
+		"CONTACT":      ProblemPattern,
+		"TIMEOUT":      ProblemType,
+		"DEBUG":        ProblemType,
+		"EXTRA":        ProblemUnknown,
+	}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("Expected %v, got %v", expected, kinds)
+	}
+
+	if err := schema.Validate(map[string]string{"PORT": "abc", "DATABASE_URL": "x"}); err == nil ||
+		!strings.Contains(err.Error(), `PORT must be an integer, got "abc"`) ||
+		!strings.Contains(err.Error(), "DATABASE_URL must be a URL") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestReadSchemaJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.json")
+	content := `{"PORT": {"type": "int", "required": true, "min": 1}, "MODE": {"enum": ["a", "b"]}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := ReadSchema(path)
+	if err != nil {
+		t.Fatalf("ReadSchema failed: %v", err)
+	}
+	if len(schema.Fields) != 2 || schema.Fields[0].Key != "MODE" || schema.Fields[1].Type != TypeInt {
+		t.Errorf("Unexpected schema: %+v", schema.Fields)
+	}
+
+	if err := schema.Validate(map[string]string{"PORT": "0", "MODE": "c"}); err == nil {
+		t.Error("Expected validation errors")
+	}
+}